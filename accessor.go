@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"strconv"
+	"time"
+)
+
+//////////////////////////////////////////////
+// typed value conversion shared by accessors
+//////////////////////////////////////////////
+
+// zero values of kinds, returned with error
+var zeroValues = map[string]interface{}{
+	"bool":     false,
+	"int":      0,
+	"uint":     uint(0),
+	"float32":  float32(0),
+	"float64":  float64(0),
+	"duration": time.Duration(0),
+}
+
+// parseValue convert string to value of kind, kind is also the type name in InvalidValueError
+func parseValue(s string, kind string) (interface{}, error) {
+	switch kind {
+	case "bool":
+		return strconv.ParseBool(s)
+	case "int":
+		return strconv.Atoi(s)
+	case "uint":
+		v, err := strconv.ParseUint(s, 10, 0)
+		return uint(v), err
+	case "float32":
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	case "float64":
+		return strconv.ParseFloat(s, 64)
+	case "duration":
+		return time.ParseDuration(s)
+	default:
+		return s, nil
+	}
+}
+
+// must panic with error
+func must(v interface{}, err error) interface{} {
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+//////////////////////////////////////////////
+// Arg accessors return error or default
+//////////////////////////////////////////////
+
+// ArgE return arg by index, error if out of range
+func (c *Context) ArgE(i int) (string, error) {
+	if i < 0 || i >= len(c.args) {
+		return "", &InvalidValueError{Index: i, Err: ErrMissingValue}
+	}
+
+	return c.args[i], nil
+}
+
+// ArgOr return arg by index, or def if out of range
+func (c *Context) ArgOr(i int, def string) string {
+	if v, err := c.ArgE(i); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// argAs return arg by index converted to kind, zero value of kind if missing or invalid
+func (c *Context) argAs(i int, kind string) (interface{}, error) {
+	s, err := c.ArgE(i)
+	if err != nil {
+		return zeroValues[kind], err
+	}
+
+	v, err := parseValue(s, kind)
+	if err != nil {
+		return zeroValues[kind], &InvalidValueError{Index: i, Value: s, Type: kind, Err: err}
+	}
+
+	return v, nil
+}
+
+// argOr return arg by index converted to kind, or def if missing or invalid
+func (c *Context) argOr(i int, kind string, def interface{}) interface{} {
+	if v, err := c.argAs(i, kind); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// ArgBoolE return bool arg by index
+func (c *Context) ArgBoolE(i int) (bool, error) {
+	v, err := c.argAs(i, "bool")
+	return v.(bool), err
+}
+
+// ArgBoolOr return bool arg by index, or def if missing or invalid
+func (c *Context) ArgBoolOr(i int, def bool) bool {
+	return c.argOr(i, "bool", def).(bool)
+}
+
+// ArgIntE return integer arg by index
+func (c *Context) ArgIntE(i int) (int, error) {
+	v, err := c.argAs(i, "int")
+	return v.(int), err
+}
+
+// ArgIntOr return integer arg by index, or def if missing or invalid
+func (c *Context) ArgIntOr(i int, def int) int {
+	return c.argOr(i, "int", def).(int)
+}
+
+// ArgUintE return uint arg by index
+func (c *Context) ArgUintE(i int) (uint, error) {
+	v, err := c.argAs(i, "uint")
+	return v.(uint), err
+}
+
+// ArgUintOr return uint arg by index, or def if missing or invalid
+func (c *Context) ArgUintOr(i int, def uint) uint {
+	return c.argOr(i, "uint", def).(uint)
+}
+
+// ArgF32E return float32 arg by index
+func (c *Context) ArgF32E(i int) (float32, error) {
+	v, err := c.argAs(i, "float32")
+	return v.(float32), err
+}
+
+// ArgF32Or return float32 arg by index, or def if missing or invalid
+func (c *Context) ArgF32Or(i int, def float32) float32 {
+	return c.argOr(i, "float32", def).(float32)
+}
+
+// ArgF64E return float64 arg by index
+func (c *Context) ArgF64E(i int) (float64, error) {
+	v, err := c.argAs(i, "float64")
+	return v.(float64), err
+}
+
+// ArgF64Or return float64 arg by index, or def if missing or invalid
+func (c *Context) ArgF64Or(i int, def float64) float64 {
+	return c.argOr(i, "float64", def).(float64)
+}
+
+// ArgDurationE return time.Duration arg by index, such as 1h30m
+func (c *Context) ArgDurationE(i int) (time.Duration, error) {
+	v, err := c.argAs(i, "duration")
+	return v.(time.Duration), err
+}
+
+// ArgDurationOr return time.Duration arg by index, or def if missing or invalid
+func (c *Context) ArgDurationOr(i int, def time.Duration) time.Duration {
+	return c.argOr(i, "duration", def).(time.Duration)
+}
+
+//////////////////////////////////////////////
+// Flag accessors return error or default
+//////////////////////////////////////////////
+
+// flagValue return value and name of flag, ok is false if flag has no value
+func (c *Context) flagValue(key string) (string, string, bool) {
+	flag := c.Flag(key)
+	if flag == nil || flag.Len() == 0 {
+		return "", key, false
+	}
+
+	return flag.Get(), flag.Name, true
+}
+
+// flagSet return true if flag has value, or bool flag appear without value
+func (c *Context) flagSet(key string, kind string) bool {
+	flag := c.Flag(key)
+	return flag != nil && (flag.Len() > 0 || kind == "bool" && flag.used)
+}
+
+// flagAs return flag converted to kind, zero value of kind if not set or invalid,
+// bool flag is true if appear without value
+func (c *Context) flagAs(key string, kind string) (interface{}, error) {
+	s, name, ok := c.flagValue(key)
+	if !ok {
+		if kind == "bool" {
+			return c.flagSet(key, kind), nil
+		}
+
+		return zeroValues[kind], nil
+	}
+
+	v, err := parseValue(s, kind)
+	if err != nil {
+		return zeroValues[kind], &InvalidValueError{Flag: name, Value: s, Type: kind, Err: err}
+	}
+
+	return v, nil
+}
+
+// flagOr return flag converted to kind, or def if not set or invalid
+func (c *Context) flagOr(key string, kind string, def interface{}) interface{} {
+	if !c.flagSet(key, kind) {
+		return def
+	}
+
+	if v, err := c.flagAs(key, kind); err == nil {
+		return v
+	}
+
+	return def
+}
+
+// FlagStrOr return string flag, or def if not set
+func (c *Context) FlagStrOr(key string, def string) string {
+	if s, _, ok := c.flagValue(key); ok {
+		return s
+	}
+
+	return def
+}
+
+// FlagBoolE return bool flag, true if flag appear without value, false if not set
+func (c *Context) FlagBoolE(key string) (bool, error) {
+	v, err := c.flagAs(key, "bool")
+	return v.(bool), err
+}
+
+// FlagBoolOr return bool flag, or def if not set or invalid
+func (c *Context) FlagBoolOr(key string, def bool) bool {
+	return c.flagOr(key, "bool", def).(bool)
+}
+
+// FlagIntE return int flag, 0 if not set
+func (c *Context) FlagIntE(key string) (int, error) {
+	v, err := c.flagAs(key, "int")
+	return v.(int), err
+}
+
+// FlagIntOr return int flag, or def if not set or invalid
+func (c *Context) FlagIntOr(key string, def int) int {
+	return c.flagOr(key, "int", def).(int)
+}
+
+// FlagUintE return uint flag, 0 if not set
+func (c *Context) FlagUintE(key string) (uint, error) {
+	v, err := c.flagAs(key, "uint")
+	return v.(uint), err
+}
+
+// FlagUintOr return uint flag, or def if not set or invalid
+func (c *Context) FlagUintOr(key string, def uint) uint {
+	return c.flagOr(key, "uint", def).(uint)
+}
+
+// FlagF32E return float32 flag, 0 if not set
+func (c *Context) FlagF32E(key string) (float32, error) {
+	v, err := c.flagAs(key, "float32")
+	return v.(float32), err
+}
+
+// FlagF32Or return float32 flag, or def if not set or invalid
+func (c *Context) FlagF32Or(key string, def float32) float32 {
+	return c.flagOr(key, "float32", def).(float32)
+}
+
+// FlagF64E return float64 flag, 0 if not set
+func (c *Context) FlagF64E(key string) (float64, error) {
+	v, err := c.flagAs(key, "float64")
+	return v.(float64), err
+}
+
+// FlagF64Or return float64 flag, or def if not set or invalid
+func (c *Context) FlagF64Or(key string, def float64) float64 {
+	return c.flagOr(key, "float64", def).(float64)
+}
+
+// FlagDurationE return time.Duration flag, 0 if not set
+func (c *Context) FlagDurationE(key string) (time.Duration, error) {
+	v, err := c.flagAs(key, "duration")
+	return v.(time.Duration), err
+}
+
+// FlagDurationOr return time.Duration flag, or def if not set or invalid
+func (c *Context) FlagDurationOr(key string, def time.Duration) time.Duration {
+	return c.flagOr(key, "duration", def).(time.Duration)
+}
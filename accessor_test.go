@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccessors(t *testing.T) {
+	var ctx *Context
+	app := New()
+	app.AddCommands([]*Command{{
+		Name: "get",
+		Flags: []*Flag{
+			{Name: "count", Short: "c"},
+			{Name: "ratio"},
+			{Name: "watch", Short: "w"},
+			{Name: "timeout"},
+			{Name: "limit"},
+			{Name: "force"},
+		},
+		Run: func(c *Context) { ctx = c },
+	}})
+
+	if err := app.RunArgs([]string{"t", "get", "3", "abc", "1.5", "2m", "-c=7", "--ratio=0.5", "--timeout=x", "-w"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ctx.ArgInt(0) != 3 || ctx.ArgUint(0) != 3 || ctx.ArgF32(2) != 1.5 || ctx.ArgF64(2) != 1.5 || ctx.ArgDuration(3) != 2*time.Minute {
+		t.Errorf("typed args fail")
+	}
+
+	if _, err := ctx.ArgIntE(1); err == nil || err.Error() != "invalid value 'abc' for argument #2, expect int" {
+		t.Errorf("invalid arg should fail, %v", err)
+	}
+
+	if _, err := ctx.ArgBoolE(9); err == nil {
+		t.Errorf("missing arg should fail")
+	}
+
+	if ctx.ArgIntOr(1, 5) != 5 || ctx.ArgIntOr(9, 6) != 6 || ctx.ArgOr(9, "x") != "x" || ctx.ArgDurationOr(1, time.Second) != time.Second {
+		t.Errorf("default of arg fail")
+	}
+
+	if ctx.FlagInt("count") != 7 || ctx.FlagIntOr("count", 1) != 7 || ctx.FlagF32("ratio") != 0.5 || ctx.FlagF64Or("ratio", 1) != 0.5 {
+		t.Errorf("typed flags fail")
+	}
+
+	if ctx.FlagInt("limit") != 0 || ctx.FlagIntOr("limit", 10) != 10 || ctx.FlagUintOr("missing", 2) != 2 {
+		t.Errorf("unset flag should be zero or default")
+	}
+
+	if _, err := ctx.FlagDurationE("timeout"); err == nil {
+		t.Errorf("invalid flag should fail")
+	}
+
+	if ctx.FlagDurationOr("timeout", time.Hour) != time.Hour {
+		t.Errorf("invalid flag should use default")
+	}
+
+	if !ctx.FlagBool("watch") || !ctx.FlagBoolOr("watch", false) || ctx.FlagBool("force") || !ctx.FlagBoolOr("force", true) {
+		t.Errorf("bool flag fail")
+	}
+
+	defer func() {
+		if _, ok := recover().(*InvalidValueError); !ok {
+			t.Errorf("invalid flag should panic with InvalidValueError")
+		}
+	}()
+
+	ctx.FlagDuration("timeout")
+}
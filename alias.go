@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+const aliasSection = "alias"
+
+// EnableAliases enable user defined aliases in config file, like git:
+// [alias]
+// co = checkout -b
+// 'app co x' will be expanded to 'app checkout -b x' before build commands,
+// built-in commands can not be overwritten by alias,
+// config file is only loaded if the first command token is not a known command,
+// bad config file is reported to error stream and aliases are not expanded
+func (app *App) EnableAliases() {
+	app.aliases = true
+}
+
+// expandAlias expand the first command token recursively, panic if alias loop
+func (app *App) expandAlias(args []string) []string {
+	var conf *config
+	visited := make([]string, 0)
+	for {
+		idx := app.firstCommandIndex(args)
+		if idx == -1 || app.root.findSub(args[idx]) != nil {
+			return args
+		}
+
+		if conf == nil {
+			c, err := loadConfig(app.ConfigFile())
+			if err != nil {
+				fmt.Fprintf(app.Err, "warning: aliases are ignored, %+v\n", err)
+				return args
+			}
+
+			conf = c
+		}
+
+		name := args[idx]
+		value := conf.Get(aliasSection, name)
+		if value == "" {
+			return args
+		}
+
+		for _, v := range visited {
+			if v == name {
+				panic(fmt.Errorf("alias loop detected: %s -> %s", strings.Join(visited, " -> "), name))
+			}
+		}
+
+		visited = append(visited, name)
+
+		expanded, err := splitArgs(value)
+		if err != nil {
+			panic(fmt.Errorf("bad alias %s, %+v", name, err))
+		}
+
+		result := make([]string, 0, len(args)+len(expanded))
+		result = append(result, args[:idx]...)
+		result = append(result, expanded...)
+		result = append(result, args[idx+1:]...)
+		args = result
+	}
+}
+
+// firstCommandIndex return index of first token which is not option or value of root flag,
+// like build, value is the next token if not attached, '-n ns co' return index of 'co'
+func (app *App) firstCommandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		style, key, value := app.parseOption(args[i])
+		if style == styleUnknown {
+			return i
+		}
+
+		if style == styleSingle && len(key) > 1 {
+			key = key[len(key)-1:]
+		}
+
+		if value == "" && app.isRootFlag(key) {
+			i++
+		}
+	}
+
+	return -1
+}
+
+// isRootFlag return true if key is name or short of root flag
+func (app *App) isRootFlag(key string) bool {
+	for _, f := range app.root.Flags {
+		if key != "" && (f.Name == key || f.Short == key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addAliasCommand add built-in command: alias list|add|remove
+func (app *App) addAliasCommand() {
+	if app.root.findSub("alias") != nil {
+		return
+	}
+
+	app.root.AddSub(&Command{
+		Name:  "alias",
+		Short: "Manage user defined command aliases.",
+		Subs: []*Command{
+			{
+				Name:  "list",
+				Short: "List all aliases",
+				Run:   app.onAliasList,
+			},
+			{
+				Name:  "add",
+				Short: "Add alias: alias add <name> <command> [<args>]",
+				Run:   app.onAliasAdd,
+			},
+			{
+				Name:  "remove",
+				Short: "Remove alias: alias remove <name>",
+				Run:   app.onAliasRemove,
+			},
+		},
+	})
+}
+
+func (app *App) onAliasList(ctx *Context) {
+	conf, err := loadConfig(app.ConfigFile())
+	if err != nil {
+		panic(err)
+	}
+
+	for _, name := range conf.Keys(aliasSection) {
+		fmt.Fprintf(ctx.Out(), "%s = %s\n", name, conf.Get(aliasSection, name))
+	}
+}
+
+func (app *App) onAliasAdd(ctx *Context) {
+	if ctx.NArg() < 2 {
+		panic(fmt.Errorf("usage: %s alias add <name> <command> [<args>]", app.Name))
+	}
+
+	name := ctx.Arg(0)
+	if name == "" || strings.ContainsAny(name, " \t=[]") || name[0] == '-' || name[0] == '/' {
+		panic(fmt.Errorf("bad alias name: %s", name))
+	}
+
+	if app.root.findSub(name) != nil {
+		panic(fmt.Errorf("alias %s conflicts with built-in command", name))
+	}
+
+	path := app.ConfigFile()
+	conf, err := loadConfig(path)
+	if err != nil {
+		panic(err)
+	}
+
+	// single arg is used as raw value, such as: alias add co "checkout -b"
+	value := joinArgs(ctx.args[1:])
+	if ctx.NArg() == 2 {
+		value = ctx.Arg(1)
+	}
+
+	if _, err := splitArgs(value); err != nil {
+		panic(fmt.Errorf("bad alias %s, %+v", name, err))
+	}
+
+	conf.Set(aliasSection, name, value)
+	if err := conf.save(path); err != nil {
+		panic(err)
+	}
+}
+
+func (app *App) onAliasRemove(ctx *Context) {
+	if ctx.NArg() < 1 {
+		panic(fmt.Errorf("usage: %s alias remove <name>", app.Name))
+	}
+
+	path := app.ConfigFile()
+	conf, err := loadConfig(path)
+	if err != nil {
+		panic(err)
+	}
+
+	if !conf.Del(aliasSection, ctx.Arg(0)) {
+		panic(fmt.Errorf("alias not found: %s", ctx.Arg(0)))
+	}
+
+	if err := conf.save(path); err != nil {
+		panic(err)
+	}
+}
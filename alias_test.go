@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newAliasApp return app with aliases in config file, and its error output
+func newAliasApp(t *testing.T, config string) (*App, *bytes.Buffer) {
+	dir, err := ioutil.TempDir("", "cli-alias")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	errOut := &bytes.Buffer{}
+	app := New()
+	app.Err = errOut
+	app.SetConfigFile(path)
+	app.EnableAliases()
+	app.AddFlags([]*Flag{{Name: "namespace", Short: "n"}, {Name: "verbose", Short: "v"}})
+	app.AddCommands([]*Command{NewCmd("checkout", "", func(ctx *Context) {})})
+	return app, errOut
+}
+
+func TestAliasSkipFlagValue(t *testing.T) {
+	app, _ := newAliasApp(t, "[alias]\nco = checkout -b\nns = checkout ns\n")
+	defer os.RemoveAll(filepath.Dir(app.ConfigFile()))
+
+	cases := map[string]string{
+		"-n ns co x":          "-n ns checkout -b x",
+		"-vn ns co x":         "-vn ns checkout -b x",
+		"--namespace=ns co x": "--namespace=ns checkout -b x",
+		"--unknown co x":      "--unknown checkout -b x",
+	}
+
+	for args, expect := range cases {
+		if got := app.expandAlias(strings.Fields(args)); !reflect.DeepEqual(got, strings.Fields(expect)) {
+			t.Errorf("expand %s, expect %s, got %q", args, expect, got)
+		}
+	}
+}
+
+func TestAliasBadConfig(t *testing.T) {
+	app, errOut := newAliasApp(t, "[alias\n")
+	defer os.RemoveAll(filepath.Dir(app.ConfigFile()))
+
+	if err := app.RunArgs([]string{"t", "checkout", "x"}); err != nil || errOut.Len() > 0 {
+		t.Errorf("known command should not load config, %+v, %s", err, errOut.String())
+	}
+
+	if got := app.expandAlias([]string{"co", "x"}); !reflect.DeepEqual(got, []string{"co", "x"}) {
+		t.Errorf("bad config should not expand, got %q", got)
+	}
+
+	if !strings.Contains(errOut.String(), "bad config") {
+		t.Errorf("bad config should be reported, %s", errOut.String())
+	}
+}
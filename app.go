@@ -1,11 +1,16 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 )
 
 const (
@@ -19,6 +24,9 @@ const (
 	AppCommandName = ""
 )
 
+// DefaultDeprecatedFormat default notice format, args are command name and deprecated message
+const DefaultDeprecatedFormat = "Command %q is deprecated, %s\n"
+
 // ProcessBar
 // http://www.gnu.org/software/bash/manual/bash.html#Programmable-Completion
 // https://github.com/cheggaaa/pb
@@ -45,11 +53,27 @@ const (
 
 // App build a git style cli
 type App struct {
-	Name      string
-	help      IHelp             // custom help
-	root      *Command          // Root Command
-	groups    map[string]string // group name to desc
-	languages map[string]string // language map
+	Name          string
+	Version       string
+	In            io.Reader              // input stream, default os.Stdin
+	Out           io.Writer              // output stream, default os.Stdout
+	Err           io.Writer              // error stream, default os.Stderr
+	help          IHelp                  // custom help
+	root          *Command               // Root Command
+	groups        map[string]string      // group name to desc
+	languages     map[string]string      // language map
+	deprecate     string                 // deprecated notice format
+	distance      int                    // max edit distance of suggestions
+	prefix        bool                   // enable unique prefix matching
+	notFound      NotFoundHandler        // default handler of unknown command
+	plugins       bool                   // enable external plugin executables
+	pluginDirs    []string               // plugin dirs searched before PATH
+	configFile    string                 // user config file
+	aliases       bool                   // enable user defined aliases
+	responseDepth int                    // max depth of response files, 0 is disabled
+	stateDir      string                 // user state dir
+	multiCall     string                 // real program name of multi-call binary
+	specDecoders  map[string]SpecDecoder // spec decoders by file extension
 }
 
 // New create new App
@@ -81,6 +105,95 @@ func (app *App) SetGroups(groups map[string]string) {
 	app.groups = groups
 }
 
+// SetDeprecatedFormat set the notice format printed before deprecated command run,
+// args are command name and deprecated message
+func (app *App) SetDeprecatedFormat(format string) {
+	app.deprecate = format
+}
+
+// SetSuggestDistance set max edit distance of "Did you mean?" suggestions,
+// 0 use DefaultSuggestDistance, negative disable suggestions
+func (app *App) SetSuggestDistance(distance int) {
+	app.distance = distance
+}
+
+// EnablePrefixMatching allow unique prefix abbreviation of commands and long flags,
+// such as 'desc' for 'describe' and '--dry' for '--dry-run'
+func (app *App) EnablePrefixMatching(enable bool) {
+	app.prefix = enable
+}
+
+// SetNotFound set default handler of unknown command,
+// used when the command can not accept args and has no NotFound handler
+func (app *App) SetNotFound(handler NotFoundHandler) {
+	app.notFound = handler
+}
+
+// SetConfigFile set user config file, default is {XDG_CONFIG_HOME}/{app}/config
+func (app *App) SetConfigFile(path string) {
+	app.configFile = path
+}
+
+// ConfigFile return user config file
+func (app *App) ConfigFile() string {
+	if app.configFile != "" {
+		return app.configFile
+	}
+
+	return filepath.Join(userConfigDir(), app.Name, "config")
+}
+
+// userConfigDir same as os.UserConfigDir which need go1.13, "." if home is unknown
+func userConfigDir() string {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("APPDATA"); dir != "" {
+			return dir
+		}
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, "Library", "Application Support")
+		}
+	default:
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			return dir
+		}
+
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".config")
+		}
+	}
+
+	return "."
+}
+
+// SetStateDir set user state dir, default is {XDG_STATE_HOME}/{app}
+func (app *App) SetStateDir(dir string) {
+	app.stateDir = dir
+}
+
+// StateDir return user state dir, used for crash reports
+func (app *App) StateDir() string {
+	if app.stateDir != "" {
+		return app.stateDir
+	}
+
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" && runtime.GOOS == "windows" {
+		dir = os.Getenv("LOCALAPPDATA")
+	}
+
+	if dir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".local", "state")
+		} else {
+			dir = os.TempDir()
+		}
+	}
+
+	return filepath.Join(dir, app.Name)
+}
+
 func (app *App) AddGroup(name string, desc string) {
 	if app.groups == nil {
 		app.groups = make(map[string]string)
@@ -121,23 +234,80 @@ func (app *App) AddFooter(data string) {
 }
 
 // Run process cli
+// exit with ExitCode of error if fail
 func (app *App) Run() {
+	if err := app.RunArgs(os.Args); err != nil {
+		switch err.(type) {
+		case *PanicError, *ExitError:
+			// already reported by Recovery or executable
+		default:
+			log.New(app.Err, "", log.LstdFlags).Printf("%+v\n", err)
+		}
+
+		os.Exit(ExitCode(err))
+	}
+}
+
+// RunArgs process cli with args, args[0] is program name like os.Args, see EnableMultiCall,
+// return the first error of command chain, or parse error
+func (app *App) RunArgs(args []string) error {
+	return app.RunArgsContext(context.Background(), args)
+}
+
+// RunArgsContext same as RunArgs, parent is the parent of Context.Ctx()
+func (app *App) RunArgsContext(parent context.Context, args []string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("%+v\n", r)
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%+v", r)
+			}
 		}
 	}()
 
 	app.setup()
-	app.build()
+	if len(args) > 0 {
+		args = append(app.multiCallArgs(args[0]), args[1:]...)
+	}
+
+	return app.build(parent, app.expandArgs(args))
 }
 
 func (app *App) setup() {
 	app.Name = strings.TrimSpace(app.Name)
+	if app.In == nil {
+		app.In = os.Stdin
+	}
+
+	if app.Out == nil {
+		app.Out = os.Stdout
+	}
+
+	if app.Err == nil {
+		app.Err = os.Stderr
+	}
+
 	if app.help == nil {
 		app.help = &Help{}
 	}
 
+	if app.deprecate == "" {
+		app.deprecate = DefaultDeprecatedFormat
+	}
+
+	if app.distance == 0 {
+		app.distance = DefaultSuggestDistance
+	}
+
+	if app.plugins {
+		app.addPluginCommand()
+	}
+
+	if app.aliases {
+		app.addAliasCommand()
+	}
+
 	if app.root.findSub("help") == nil {
 		app.root.AddSub(&Command{
 			Name: "help",
@@ -148,7 +318,22 @@ func (app *App) setup() {
 	}
 }
 
-func (app *App) build() {
+// expandArgs expand args before build commands
+func (app *App) expandArgs(args []string) []string {
+	if app.responseDepth > 0 {
+		args = app.expandResponseFiles(args, ".", 0)
+	}
+
+	if app.aliases {
+		args = app.expandAlias(args)
+	}
+
+	return args
+}
+
+func (app *App) build(parent context.Context, argv []string) error {
+	start := time.Now()
+
 	// -v --verbose
 	// -I/usr/include -I=/usr/include -I /usr/include
 	// -aux
@@ -156,11 +341,80 @@ func (app *App) build() {
 	// /v:{on/off}
 
 	// find command list and args
-	cmds, args := app.buildCommands()
+	cmds, args, handled, err := app.buildCommands(parent, argv)
+	if handled {
+		return err
+	}
+
 	// merge flags from all commands
 	flags := app.buildAllFlags(cmds)
 
 	// build options and params
+	options, params, isHelp := app.parseOptions(cmds, flags, args)
+
+	// remove root
+	cmds = cmds[1:]
+
+	if !isHelp && len(cmds) == 0 && app.root.findSub(AppCommandName) == nil {
+		// no command and no action use help
+		isHelp = true
+	}
+
+	if len(cmds) > 0 && cmds[0].Name == "help" {
+		isHelp = true
+		cmds = cmds[1:]
+	}
+
+	// check flags required, and add flags which has default or env value,
+	// flags map has both name and short, so each flag is validated once
+	validated := make(map[*Flag]bool)
+	for _, flag := range flags {
+		if validated[flag] {
+			continue
+		}
+
+		validated[flag] = true
+		if err := flag.validate(); err != nil && !isHelp {
+			panic(err)
+		}
+
+		if flag.Len() > 0 {
+			options[flag.Name] = flag
+		}
+	}
+
+	if !isHelp && len(cmds) == 0 {
+		// run app command
+		cmds = append(cmds, app.root.findSub(AppCommandName))
+	}
+
+	if !isHelp {
+		if err := cmds[len(cmds)-1].checkArgs(params); err != nil {
+			panic(err)
+		}
+	}
+
+	ctx := newContext(app, params, cmds, options)
+	ctx.argv = argv
+	ctx.all = flags
+	ctx.parent = parent
+	ctx.start = start
+	defer ctx.release()
+
+	if isHelp {
+		ctx.runCommand(app.root.findSub("help"))
+	} else {
+		defer func() {
+			ctx.runFinally(recover())
+		}()
+		ctx.Next()
+	}
+
+	return ctx.Err()
+}
+
+// parseOptions parse options of command chain, return options, params and whether help flag appear
+func (app *App) parseOptions(cmds []*Command, flags map[string]*Flag, args []string) (map[string]*Flag, []string, bool) {
 	isHelp := false
 	options := make(map[string]*Flag)
 	params := make([]string, 0, len(args))
@@ -192,15 +446,12 @@ func (app *App) build() {
 				st := string(ch)
 				flag = flags[st]
 				if flag == nil {
-					panic(fmt.Errorf("Unknown option %+v", st))
+					panic(app.unknownFlag(cmds, "-", st))
 				}
 				options[flag.Name] = flag
 			}
 		} else {
-			flag = flags[key]
-			if flag == nil {
-				panic(fmt.Errorf("Unknown option %+v", key))
-			}
+			flag = app.findFlag(cmds, flags, style, key)
 			options[flag.Name] = flag
 		}
 
@@ -210,7 +461,7 @@ func (app *App) build() {
 		nextIdx := idx + 1
 		if value == "" && nextIdx < len(args) {
 			nextStr := args[nextIdx]
-			if nextStr[0] != '-' && nextStr[0] != '/' {
+			if nextStr == "" || (nextStr[0] != '-' && nextStr[0] != '/') {
 				value = nextStr
 				idx = nextIdx
 			}
@@ -221,49 +472,50 @@ func (app *App) build() {
 		}
 	}
 
-	// check flags required
-	for _, flag := range flags {
-		if err := flag.validate(); err != nil {
-			panic(err)
-		}
-	}
-
-	// remove root
-	cmds = cmds[1:]
-
-	if !isHelp && len(cmds) == 0 && app.root.findSub(AppCommandName) == nil {
-		// no command and no action use help
-		isHelp = true
-	}
-
-	if len(cmds) > 0 && cmds[0].Name == "help" {
-		isHelp = true
-		cmds = cmds[1:]
-	}
-
-	ctx := newContext(app, params, cmds, options)
-
-	if isHelp {
-		app.root.findSub("help").Run(ctx)
-	} else {
-		ctx.Next()
-	}
+	return options, params, isHelp
 }
 
-func (app *App) buildCommands() ([]*Command, []string) {
+// buildCommands find command list and args, return handled if unknown command is processed by NotFound handler,
+// with the error passed to Context.AbortWithError by handler,
+// panic UnknownCommandError if the command has sub commands but can not accept args and has no handler
+func (app *App) buildCommands(parent context.Context, argv []string) ([]*Command, []string, bool, error) {
 	cmds := []*Command{app.root}
-	args := make([]string, 0, len(os.Args))
+	args := make([]string, 0, len(argv))
 	last := app.root
-	for idx := 1; idx < len(os.Args); idx++ {
-		str := os.Args[idx]
-		if str[0] == '/' || str[0] == '-' {
+	for idx := 0; idx < len(argv); idx++ {
+		str := argv[idx]
+		if str == "" || str[0] == '/' || str[0] == '-' {
 			args = append(args, str)
 			continue
 		}
 
-		sub := last.findSub(str)
+		sub := app.findCommand(cmds, last, str)
 		if sub == nil {
-			args = append(args, os.Args[idx:]...)
+			if handler := app.findNotFound(cmds, last); handler != nil {
+				// flags before unknown command are parsed for handler
+				remain := argv[idx+1:]
+				flags := app.buildAllFlags(cmds)
+				options, _, _ := app.parseOptions(cmds, flags, args)
+				ctx := newContext(app, remain, cmds[1:], options)
+				ctx.argv = argv
+				ctx.opts = args
+				ctx.all = flags
+				ctx.parent = parent
+				ctx.start = time.Now()
+				defer ctx.release()
+				handler(ctx, str, remain)
+				return nil, nil, true, ctx.Err()
+			}
+
+			if len(last.Subs) > 0 && !app.isRunnable(last) {
+				panic(&UnknownCommandError{
+					Name:        str,
+					Path:        app.commandPath(cmds),
+					Suggestions: app.suggestCommands(last, str),
+				})
+			}
+
+			args = append(args, argv[idx:]...)
 			break
 		}
 
@@ -273,19 +525,63 @@ func (app *App) buildCommands() ([]*Command, []string) {
 		}
 	}
 
-	return cmds, args
+	return cmds, args, false, nil
+}
+
+// findNotFound return NotFound handler of command, or app default handler if command can not accept args,
+// unknown command after help never use handler
+func (app *App) findNotFound(cmds []*Command, cmd *Command) NotFoundHandler {
+	for _, c := range cmds {
+		if c.Name == "help" {
+			return nil
+		}
+	}
+
+	if cmd.NotFound != nil {
+		return cmd.NotFound
+	}
+
+	if app.isRunnable(cmd) {
+		return nil
+	}
+
+	if app.plugins {
+		return app.onPluginNotFound
+	}
+
+	return app.notFound
+}
+
+// onPluginNotFound run plugin, or fallback to app default handler and unknown command error
+func (app *App) onPluginNotFound(ctx *Context, name string, args []string) {
+	if app.runPlugin(ctx, name, args) {
+		return
+	}
+
+	if app.notFound != nil {
+		app.notFound(ctx, name, args)
+		return
+	}
+
+	cmds := append([]*Command{app.root}, ctx.CommandList()...)
+	panic(&UnknownCommandError{
+		Name:        name,
+		Path:        app.commandPath(cmds),
+		Suggestions: app.suggestCommands(cmds[len(cmds)-1], name),
+	})
 }
 
 func (app *App) buildAllFlags(cmds []*Command) map[string]*Flag {
 	flags := make(map[string]*Flag)
 	for _, cmd := range cmds {
 		for _, f := range cmd.Flags {
+			f.reset()
 			if f.Name != "" {
 				flags[f.Name] = f
 			}
 
 			if f.Short != "" {
-				flags[f.Name] = f
+				flags[f.Short] = f
 			}
 		}
 	}
@@ -293,7 +589,98 @@ func (app *App) buildAllFlags(cmds []*Command) map[string]*Flag {
 	return flags
 }
 
+// findCommand find sub command by name, alias or unique prefix if enabled
+func (app *App) findCommand(cmds []*Command, cmd *Command, name string) *Command {
+	if sub := cmd.findSub(name); sub != nil || !app.prefix {
+		return sub
+	}
+
+	sub, candidates := cmd.findSubByPrefix(name)
+	if len(candidates) > 1 {
+		panic(&AmbiguousError{Kind: "command", Name: name, Path: app.commandPath(cmds), Candidates: candidates})
+	}
+
+	return sub
+}
+
+// findFlag find flag by name or unique prefix of long name if enabled, panic if not found
+func (app *App) findFlag(cmds []*Command, flags map[string]*Flag, style int, key string) *Flag {
+	if flag := flags[key]; flag != nil {
+		return flag
+	}
+
+	if app.prefix && style != styleSingle {
+		var found *Flag
+		candidates := make([]string, 0)
+		for name, f := range flags {
+			if name == f.Name && strings.HasPrefix(name, key) {
+				found = f
+				candidates = append(candidates, optionPrefix(style)+name)
+			}
+		}
+
+		if len(candidates) == 1 {
+			return found
+		}
+
+		if len(candidates) > 1 {
+			sort.Strings(candidates)
+			panic(&AmbiguousError{Kind: "flag", Name: optionPrefix(style) + key, Path: app.commandPath(cmds), Candidates: candidates})
+		}
+	}
+
+	panic(app.unknownFlag(cmds, optionPrefix(style), key))
+}
+
+// isRunnable return true if command can accept args
+func (app *App) isRunnable(cmd *Command) bool {
+	if cmd == app.root {
+		return cmd.hasAction() || cmd.findSub(AppCommandName) != nil
+	}
+
+	return cmd.hasAction()
+}
+
+// commandPath return app name and command names joined by space, ignore help
+func (app *App) commandPath(cmds []*Command) string {
+	names := []string{app.Name}
+	for _, cmd := range cmds {
+		if cmd == app.root || cmd.Name == "help" || cmd.Name == AppCommandName {
+			continue
+		}
+
+		names = append(names, cmd.Name)
+	}
+
+	return strings.Join(names, " ")
+}
+
+// unknownFlag create UnknownFlagError with suggestions, short flag has no suggestion
+func (app *App) unknownFlag(cmds []*Command, prefix string, key string) error {
+	err := &UnknownFlagError{Name: prefix + key, Path: app.commandPath(cmds)}
+	if len(key) > 1 {
+		err.Suggestions = app.suggestFlags(cmds, key)
+	}
+
+	return err
+}
+
+func optionPrefix(style int) string {
+	switch style {
+	case styleWindow:
+		return "/"
+	case styleSingle:
+		return "-"
+	default:
+		return "--"
+	}
+}
+
 func (app *App) parseOption(str string) (int, string, string) {
+	if str == "" {
+		return styleUnknown, "", ""
+	}
+
 	ch := str[0]
 	if ch != '-' && ch != '/' {
 		return styleUnknown, "", ""
@@ -348,3 +735,9 @@ func (app *App) Translate(name string, alternative string) string {
 
 	return ""
 }
+
+// printDeprecated print notice of deprecated command to error stream
+func (app *App) printDeprecated(ctx *Context, cmd *Command) {
+	msg := app.Translate(cmd.Deprecated, cmd.Name+"_d")
+	fmt.Fprintf(ctx.ErrOut(), app.deprecate, cmd.Name, msg)
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestEmptyArg(t *testing.T) {
+	var args []string
+	var name string
+	app := New()
+	cmd := NewCmd("get", "", func(ctx *Context) {
+		args = ctx.Args()
+		name = ctx.FlagStr("name")
+	})
+	cmd.Flags = []*Flag{{Name: "name"}}
+	app.AddCommands([]*Command{cmd})
+
+	if err := app.RunArgs([]string{"t", "get", "", "a"}); err != nil {
+		t.Fatalf("empty arg should not fail, %+v", err)
+	}
+
+	if !reflect.DeepEqual(args, []string{"", "a"}) {
+		t.Errorf("empty arg should be kept, %q", args)
+	}
+
+	if err := app.RunArgs([]string{"t", "get", "--name", "", "a"}); err != nil {
+		t.Fatalf("empty flag value should not fail, %+v", err)
+	}
+
+	if name != "" || !reflect.DeepEqual(args, []string{"a"}) {
+		t.Errorf("empty flag value should be consumed, name %q, args %q", name, args)
+	}
+}
+
+func TestShortEnvFlag(t *testing.T) {
+	os.Setenv("CLI_TEST_TAGS", "a")
+	defer os.Unsetenv("CLI_TEST_TAGS")
+
+	var tags []string
+	var bound struct {
+		Tags []string `cli:"tags"`
+	}
+
+	app := New()
+	cmd := NewCmd("get", "", func(ctx *Context) {
+		tags = ctx.FlagList("tags")
+		ctx.Bind(&bound)
+	})
+	cmd.Flags = []*Flag{{Name: "tags", Short: "t", Env: "CLI_TEST_TAGS", Multiple: true}}
+	app.AddCommands([]*Command{cmd})
+	if err := app.RunArgs([]string{"t", "get"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tags, []string{"a"}) || !reflect.DeepEqual(bound.Tags, []string{"a"}) {
+		t.Errorf("env should be loaded once, flag %q, bind %q", tags, bound.Tags)
+	}
+}
+
+func TestConfigFileXDG(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("XDG_CONFIG_HOME is only used on unix")
+	}
+
+	old, ok := os.LookupEnv("XDG_CONFIG_HOME")
+	os.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	defer func() {
+		if ok {
+			os.Setenv("XDG_CONFIG_HOME", old)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	}()
+
+	app := New()
+	app.Name = "t"
+	if path := app.ConfigFile(); path != filepath.Join("/tmp/xdg", "t", "config") {
+		t.Errorf("bad config file %s", path)
+	}
+}
+
+func TestNotFoundFlags(t *testing.T) {
+	var name, ns string
+	var args []string
+	app := New()
+	app.AddFlags([]*Flag{{Name: "namespace", Short: "n"}})
+	app.AddCommands([]*Command{NewCmd("get", "", func(ctx *Context) {})})
+	app.SetNotFound(func(ctx *Context, token string, remain []string) {
+		name, ns, args = token, ctx.FlagStr("namespace"), remain
+	})
+
+	if err := app.RunArgs([]string{"t", "--namespace=kube", "foo", "bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if name != "foo" || ns != "kube" || !reflect.DeepEqual(args, []string{"bar"}) {
+		t.Errorf("flags before unknown command should be parsed, name %s, namespace %s, args %q", name, ns, args)
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"strings"
+)
+
+// Arg positional argument of command, used by help usage and required check
+type Arg struct {
+	Name     string // Like 'resource' show as <resource>
+	Usage    string // describe
+	Required bool   // missing arg produces usage error
+	Rest     bool   // take all remaining args, must be the last
+}
+
+// usage return like '<name>', '[<name>]' or '[<name>...]'
+func (a *Arg) usage() string {
+	str := "<" + a.Name + ">"
+	if a.Rest {
+		str += "..."
+	}
+
+	if !a.Required {
+		str = "[" + str + "]"
+	}
+
+	return str
+}
+
+// ArgsUsage return usage of args, '[<args>]' if no args declared
+func (cmd *Command) ArgsUsage() string {
+	if len(cmd.Args) == 0 {
+		return "[<args>]"
+	}
+
+	usages := make([]string, 0, len(cmd.Args))
+	for _, arg := range cmd.Args {
+		usages = append(usages, arg.usage())
+	}
+
+	return strings.Join(usages, " ")
+}
+
+// checkArgs return InvalidValueError of the first missing required arg
+func (cmd *Command) checkArgs(params []string) error {
+	for i, arg := range cmd.Args {
+		if arg.Required && i >= len(params) {
+			return &InvalidValueError{Index: i, Arg: arg.Name, Err: ErrMissingValue}
+		}
+	}
+
+	return nil
+}
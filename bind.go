@@ -0,0 +1,449 @@
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	unmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Binder bind itself without reflection, such as code generated by bindgen
+type Binder interface {
+	Bind(ctx *Context) error
+}
+
+// BindE bind flags to struct pointer, return BindError listing all failed fields
+// field name rule:
+//   - `cli:"name"` or kebab case of field name, `cli:"-"` is ignored
+//   - nested struct with cli tag use name as prefix, like 'db-host' for field Host of field DB `cli:"db"`,
+//     struct without cli tag is ignored, such as *http.Client
+//   - embedded struct has no prefix, unless cli tag is set
+//   - field of other types, like interface, func and chan, is ignored
+//
+// support field types:
+//   - basic type, time.Duration and encoding.TextUnmarshaler
+//   - pointer of them, nil if flag is not set
+//   - []T, and map[K]V split by '=' or ':'
+//
+// positional args are bound by arg tag, with the same conversion rules:
+//   - `arg:"0"` bind arg by index
+//   - `arg:"resource"` bind the next arg in field order, after the previous arg field
+//   - `arg:"rest"` bind all args after the last arg field, to slice or joined string
+//   - `required:"true"` missing arg produces error
+//
+// fields are validated after binding, except fields fail to convert, errors of both are returned in one BindError,
+// see validateField for validation tags,
+// Binder is used instead of reflection if implemented
+func (c *Context) BindE(flags interface{}) error {
+	if binder, ok := flags.(Binder); ok {
+		return binder.Bind(c)
+	}
+
+	value := reflect.ValueOf(flags)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("bind must be pointer")
+	}
+
+	if value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind just support struct")
+	}
+
+	err := &BindError{}
+	layout := &argLayout{}
+	layout.scan(value.Elem().Type())
+	layout.next = 0
+	c.bindStruct(value.Elem(), "", layout, err)
+	layout.next = 0
+	c.validateStruct(value.Elem(), "", layout, err)
+
+	if len(err.Errors) > 0 {
+		return err
+	}
+
+	return nil
+}
+
+// bindStruct bind all fields of struct, return true if any field is set
+func (c *Context) bindStruct(value reflect.Value, prefix string, layout *argLayout, berr *BindError) bool {
+	set := false
+	vtype := value.Type()
+	for i := 0; i < vtype.NumField(); i++ {
+		sf := vtype.Field(i)
+		name, ok := fieldName(sf)
+		if !ok {
+			continue
+		}
+
+		field := value.Field(i)
+		if tag, ok := sf.Tag.Lookup("arg"); ok {
+			if c.bindArg(field, sf, tag, layout, berr) {
+				set = true
+			}
+
+			continue
+		}
+
+		if isNestedField(sf) {
+			nested := nestedPrefix(sf, prefix, name)
+			if sf.Type.Kind() == reflect.Ptr {
+				tmp := reflect.New(sf.Type.Elem())
+				if c.bindStruct(tmp.Elem(), nested, layout, berr) {
+					field.Set(tmp)
+					set = true
+				}
+			} else if c.bindStruct(field, nested, layout, berr) {
+				set = true
+			}
+
+			continue
+		}
+
+		f := c.Flag(prefix + name)
+		if f == nil || !isFieldType(sf.Type) {
+			continue
+		}
+
+		values := f.GetList()
+		if len(values) == 0 {
+			if !f.used || !isBoolType(sf.Type) {
+				continue
+			}
+
+			// bool flag appear without value
+			values = []string{"true"}
+		}
+
+		if err := bindField(field, values); err != nil {
+			berr.Errors = append(berr.Errors, &InvalidValueError{Flag: f.Name, Value: strings.Join(values, ","), Type: sf.Type.String(), Err: err})
+			continue
+		}
+
+		set = true
+	}
+
+	return set
+}
+
+// argLayout position of arg fields, in field order
+type argLayout struct {
+	next int // position of next named arg
+	rest int // start position of rest args
+}
+
+// position return position of arg field, -1 if rest
+func (l *argLayout) position(tag string) int {
+	if tag == "rest" {
+		return -1
+	}
+
+	pos := l.next
+	if n, err := strconv.Atoi(tag); err == nil && n >= 0 {
+		pos = n
+	}
+
+	if pos+1 > l.next {
+		l.next = pos + 1
+	}
+
+	return pos
+}
+
+// scan compute start position of rest args
+func (l *argLayout) scan(vtype reflect.Type) {
+	l.walk(vtype)
+	l.rest = l.next
+}
+
+// walk advance positions of all arg fields in struct
+func (l *argLayout) walk(vtype reflect.Type) {
+	for i := 0; i < vtype.NumField(); i++ {
+		sf := vtype.Field(i)
+		if _, ok := fieldName(sf); !ok {
+			continue
+		}
+
+		if tag, ok := sf.Tag.Lookup("arg"); ok {
+			l.position(tag)
+		} else if isNestedField(sf) {
+			ftype := sf.Type
+			if ftype.Kind() == reflect.Ptr {
+				ftype = ftype.Elem()
+			}
+
+			l.walk(ftype)
+		}
+	}
+}
+
+// bindArg bind positional arg to field, return true if field is set
+func (c *Context) bindArg(field reflect.Value, sf reflect.StructField, tag string, layout *argLayout, berr *BindError) bool {
+	name := argName(sf, tag)
+	required, _ := strconv.ParseBool(sf.Tag.Get("required"))
+	pos := layout.position(tag)
+
+	var values []string
+	if pos == -1 {
+		pos = layout.rest
+		if pos < len(c.args) {
+			values = c.args[pos:]
+		}
+
+		if len(values) > 0 && sf.Type.Kind() == reflect.String {
+			values = []string{strings.Join(values, " ")}
+		}
+	} else if pos < len(c.args) {
+		values = c.args[pos : pos+1]
+	}
+
+	if len(values) == 0 {
+		if required {
+			berr.Errors = append(berr.Errors, &InvalidValueError{Index: pos, Arg: name, Err: ErrMissingValue})
+		}
+
+		return false
+	}
+
+	if err := bindField(field, values); err != nil {
+		berr.Errors = append(berr.Errors, &InvalidValueError{Index: pos, Arg: name, Value: strings.Join(values, " "), Type: sf.Type.String(), Err: err})
+		return false
+	}
+
+	return true
+}
+
+// argName return name of arg field
+func argName(sf reflect.StructField, tag string) string {
+	if _, err := strconv.Atoi(tag); err == nil || tag == "rest" || tag == "" {
+		return toKebabCase(sf.Name)
+	}
+
+	return tag
+}
+
+// fieldName return flag name of field, false if field is ignored
+func fieldName(sf reflect.StructField) (string, bool) {
+	if sf.PkgPath != "" && !sf.Anonymous {
+		// unexported
+		return "", false
+	}
+
+	name := sf.Tag.Get("cli")
+	if name == "-" {
+		return "", false
+	}
+
+	if name == "" {
+		name = toKebabCase(sf.Name)
+	}
+
+	return name, true
+}
+
+// nestedPrefix return name prefix of fields in nested struct
+func nestedPrefix(sf reflect.StructField, prefix string, name string) string {
+	if sf.Anonymous && sf.Tag.Get("cli") == "" {
+		return prefix
+	}
+
+	return prefix + name + "-"
+}
+
+// isNestedType return true if type is struct or pointer of struct which is not a value type
+func isNestedType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && !isUnmarshaler(t)
+}
+
+// isNestedField return true if field is nested struct, which is embedded or has cli tag
+func isNestedField(sf reflect.StructField) bool {
+	return isNestedType(sf.Type) && (sf.Anonymous || sf.Tag.Get("cli") != "")
+}
+
+// isFieldType return true if type can be bound by bindField
+func isFieldType(t reflect.Type) bool {
+	if isUnmarshaler(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		return isValueType(t.Elem())
+	case reflect.Map:
+		return isValueType(t.Key()) && isValueType(t.Elem())
+	default:
+		return isValueType(t)
+	}
+}
+
+// isValueType return true if type can be bound by bindValue
+func isValueType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType || isUnmarshaler(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// resetStruct set bound fields to zero value, so struct can be bound again,
+// other fields like client are kept
+func resetStruct(value reflect.Value) {
+	vtype := value.Type()
+	for i := 0; i < vtype.NumField(); i++ {
+		sf := vtype.Field(i)
+		if _, ok := fieldName(sf); !ok {
+			continue
+		}
+
+		field := value.Field(i)
+		_, isArg := sf.Tag.Lookup("arg")
+		switch {
+		case isNestedField(sf) && !isArg:
+			if sf.Type.Kind() == reflect.Ptr {
+				field.Set(reflect.Zero(sf.Type))
+			} else {
+				resetStruct(field)
+			}
+		case isFieldType(sf.Type):
+			field.Set(reflect.Zero(sf.Type))
+		}
+	}
+}
+
+func isUnmarshaler(t reflect.Type) bool {
+	return t.Implements(unmarshalType) || reflect.PtrTo(t).Implements(unmarshalType)
+}
+
+func isBoolType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Bool
+}
+
+// bindField bind values to field, support slice and map
+func bindField(field reflect.Value, values []string) error {
+	ftype := field.Type()
+	if isUnmarshaler(ftype) {
+		return bindValue(values[0], field)
+	}
+
+	switch ftype.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(ftype, len(values), len(values))
+		for i, str := range values {
+			if err := bindValue(str, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(ftype)
+		for _, str := range values {
+			index := strings.IndexAny(str, "=:")
+			if index == -1 {
+				return fmt.Errorf("map value must be split by '=' or ':'")
+			}
+
+			key := reflect.New(ftype.Key()).Elem()
+			if err := bindValue(str[:index], key); err != nil {
+				return err
+			}
+
+			val := reflect.New(ftype.Elem()).Elem()
+			if err := bindValue(str[index+1:], val); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(key, val)
+		}
+
+		field.Set(m)
+	default:
+		return bindValue(values[0], field)
+	}
+
+	return nil
+}
+
+// bindValue bind the value of basic type, time.Duration, encoding.TextUnmarshaler and pointer of them
+func bindValue(str string, value reflect.Value) error {
+	vtype := value.Type()
+	if vtype.Kind() == reflect.Ptr {
+		ptr := reflect.New(vtype.Elem())
+		if err := bindValue(str, ptr.Elem()); err != nil {
+			return err
+		}
+
+		value.Set(ptr)
+		return nil
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(unmarshalType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+
+	if vtype == durationType {
+		v, err := time.ParseDuration(str)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(v))
+		return nil
+	}
+
+	switch vtype.Kind() {
+	case reflect.String:
+		value.SetString(str)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		value.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(str, 10, vtype.Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(str, 10, vtype.Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(str, vtype.Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(v)
+	default:
+		return fmt.Errorf("not support type:%+v", vtype)
+	}
+
+	return nil
+}
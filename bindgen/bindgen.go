@@ -0,0 +1,607 @@
+// Package bindgen generate flag definitions and reflection-free bind function
+// for structs tagged like cli.Context.Bind, used by go generate:
+//
+//	//go:generate go run github.com/jeckbjy/cli/cmd/clibindgen -type=CreateFlags
+//
+// for each type T, generated code implement cli.FlagSource and cli.Binder:
+//
+//	func (v *T) Flags() []*cli.Flag
+//	func (v *T) Bind(ctx *cli.Context) error
+//
+// so cli.Command.AddStructFlags and cli.Context.Bind use them without reflection.
+// supported field types are basic types, time.Duration, pointers and slices of them,
+// map[string]V of them, and nested structs declared in the same package,
+// validation tags are not supported.
+package bindgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config of generator
+type Config struct {
+	Dir   string   // package dir
+	Types []string // struct type names
+}
+
+// parser of basic type: call format, parsed type, cast
+type basicParser struct {
+	call   string
+	parsed string
+	cast   string
+}
+
+var basics = map[string]basicParser{
+	"bool":          {"strconv.ParseBool(%s)", "bool", ""},
+	"int":           {"strconv.ParseInt(%s, 10, 0)", "int64", "int"},
+	"int8":          {"strconv.ParseInt(%s, 10, 8)", "int64", "int8"},
+	"int16":         {"strconv.ParseInt(%s, 10, 16)", "int64", "int16"},
+	"int32":         {"strconv.ParseInt(%s, 10, 32)", "int64", "int32"},
+	"rune":          {"strconv.ParseInt(%s, 10, 32)", "int64", "rune"},
+	"int64":         {"strconv.ParseInt(%s, 10, 64)", "int64", ""},
+	"uint":          {"strconv.ParseUint(%s, 10, 0)", "uint64", "uint"},
+	"uint8":         {"strconv.ParseUint(%s, 10, 8)", "uint64", "uint8"},
+	"byte":          {"strconv.ParseUint(%s, 10, 8)", "uint64", "byte"},
+	"uint16":        {"strconv.ParseUint(%s, 10, 16)", "uint64", "uint16"},
+	"uint32":        {"strconv.ParseUint(%s, 10, 32)", "uint64", "uint32"},
+	"uint64":        {"strconv.ParseUint(%s, 10, 64)", "uint64", ""},
+	"float32":       {"strconv.ParseFloat(%s, 32)", "float64", "float32"},
+	"float64":       {"strconv.ParseFloat(%s, 64)", "float64", ""},
+	"time.Duration": {"time.ParseDuration(%s)", "time.Duration", ""},
+}
+
+const cliPackage = "github.com/jeckbjy/cli"
+
+var validateTags = []string{"min", "max", "oneof", "regex", "nonempty", "file-exists", "dir-exists"}
+
+// Generate return formatted source of all types
+func Generate(conf Config) ([]byte, error) {
+	if conf.Dir == "" {
+		conf.Dir = "."
+	}
+
+	g := &generator{structs: make(map[string]*ast.StructType), imports: make(map[string]bool)}
+	if err := g.parse(conf.Dir); err != nil {
+		return nil, err
+	}
+
+	body := &bytes.Buffer{}
+	for _, name := range conf.Types {
+		if err := g.generate(body, name); err != nil {
+			return nil, err
+		}
+	}
+
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, strconv.Quote(path))
+	}
+
+	sort.Strings(imports)
+	imports = append(imports, "", strconv.Quote(cliPackage))
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by clibindgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %s\n\n", g.pkg)
+	fmt.Fprintf(out, "import (\n%s\n)\n", strings.Join(imports, "\n"))
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code fail, %+v\n%s", err, out.String())
+	}
+
+	return src, nil
+}
+
+// generator state of one package
+type generator struct {
+	pkg     string
+	structs map[string]*ast.StructType
+	imports map[string]bool
+	tmp     int
+}
+
+// parse collect all struct types of package, test files are ignored
+func (g *generator) parse(dir string) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	for name, pkg := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+
+		g.pkg = name
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						g.structs[ts.Name.Name] = st
+					}
+				}
+			}
+		}
+	}
+
+	if g.pkg == "" {
+		return fmt.Errorf("no go package in %s", dir)
+	}
+
+	return nil
+}
+
+// field of struct after tags parsed
+type field struct {
+	name     string // go field name
+	typ      ast.Expr
+	tag      reflect.StructTag
+	embedded bool
+}
+
+// fields return all exported fields of struct, same rule as cli.Context.Bind
+func (g *generator) fields(st *ast.StructType) ([]field, error) {
+	result := make([]field, 0)
+	for _, f := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			tag = reflect.StructTag(raw)
+		}
+
+		if tag.Get("cli") == "-" {
+			continue
+		}
+
+		for _, v := range validateTags {
+			if _, ok := tag.Lookup(v); ok {
+				return nil, fmt.Errorf("validation tag %s is not supported", v)
+			}
+		}
+
+		if len(f.Names) == 0 {
+			name := types.ExprString(f.Type)
+			name = name[strings.LastIndex(name, ".")+1:]
+			name = strings.TrimPrefix(name, "*")
+			result = append(result, field{name: name, typ: f.Type, tag: tag, embedded: true})
+			continue
+		}
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			result = append(result, field{name: ident.Name, typ: f.Type, tag: tag})
+		}
+	}
+
+	return result, nil
+}
+
+// flagName return name of field
+func (f *field) flagName() string {
+	if name := f.tag.Get("cli"); name != "" {
+		return name
+	}
+
+	return kebabCase(f.name)
+}
+
+// isNested return true if nested struct field is embedded or has cli tag, same rule as cli.Context.Bind
+func (f *field) isNested() bool {
+	return f.embedded || f.tag.Get("cli") != ""
+}
+
+// nestedType return the struct name if field is nested struct or pointer of nested struct
+func (g *generator) nestedType(expr ast.Expr) (string, bool) {
+	star, isPtr := expr.(*ast.StarExpr)
+	if isPtr {
+		expr = star.X
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+
+	if _, ok := g.structs[ident.Name]; !ok {
+		return "", false
+	}
+
+	return ident.Name, isPtr
+}
+
+// typeInfo describe leaf field type
+type typeInfo struct {
+	kind  string // basic, ptr, slice, map
+	basic string // basic type name
+	src   string // type source, like []int
+}
+
+func (g *generator) leafType(expr ast.Expr) (*typeInfo, error) {
+	info := &typeInfo{src: types.ExprString(expr)}
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		info.kind = "ptr"
+		expr = t.X
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return nil, fmt.Errorf("array %s is not supported", info.src)
+		}
+
+		info.kind = "slice"
+		expr = t.Elt
+	case *ast.MapType:
+		if types.ExprString(t.Key) != "string" {
+			return nil, fmt.Errorf("map key of %s must be string", info.src)
+		}
+
+		info.kind = "map"
+		expr = t.Value
+	default:
+		info.kind = "basic"
+	}
+
+	info.basic = types.ExprString(expr)
+	if _, ok := basics[info.basic]; !ok && info.basic != "string" {
+		return nil, fmt.Errorf("type %s is not supported", info.src)
+	}
+
+	if info.basic == "time.Duration" {
+		g.imports["time"] = true
+	}
+
+	return info, nil
+}
+
+// walkArgs advance positions of arg fields, return next position
+func (g *generator) walkArgs(name string, next int) (int, error) {
+	fields, err := g.fields(g.structs[name])
+	if err != nil {
+		return 0, err
+	}
+
+	for _, f := range fields {
+		if tag, ok := f.tag.Lookup("arg"); ok {
+			_, next = argPosition(tag, next)
+		} else if nested, _ := g.nestedType(f.typ); nested != "" && f.isNested() {
+			if next, err = g.walkArgs(nested, next); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return next, nil
+}
+
+// argPosition return position of arg and next position, -1 if rest
+func argPosition(tag string, next int) (int, int) {
+	if tag == "rest" {
+		return -1, next
+	}
+
+	pos := next
+	if n, err := strconv.Atoi(tag); err == nil && n >= 0 {
+		pos = n
+	}
+
+	if pos+1 > next {
+		next = pos + 1
+	}
+
+	return pos, next
+}
+
+// typeState state of generating one type
+type typeState struct {
+	flags *bytes.Buffer
+	bind  *bytes.Buffer
+	next  int      // next arg position
+	rest  int      // rest arg position
+	sets  []string // set flags of enclosing pointer structs
+}
+
+func (g *generator) generate(out *bytes.Buffer, name string) error {
+	if _, ok := g.structs[name]; !ok {
+		return fmt.Errorf("struct %s not found", name)
+	}
+
+	rest, err := g.walkArgs(name, 0)
+	if err != nil {
+		return fmt.Errorf("%s: %+v", name, err)
+	}
+
+	state := &typeState{flags: &bytes.Buffer{}, bind: &bytes.Buffer{}, rest: rest}
+	if err := g.genStruct(state, name, "v.", ""); err != nil {
+		return fmt.Errorf("%s: %+v", name, err)
+	}
+
+	fmt.Fprintf(out, "\n// Flags return flags generated from %s\n", name)
+	fmt.Fprintf(out, "func (v *%s) Flags() []*cli.Flag {\n\treturn []*cli.Flag{\n%s\t}\n}\n", name, state.flags.String())
+	fmt.Fprintf(out, "\n// Bind bind flags and args of ctx to %s without reflection\n", name)
+	fmt.Fprintf(out, "func (v *%s) Bind(ctx *cli.Context) error {\n\terrs := &cli.BindError{}\n%s", name, state.bind.String())
+	fmt.Fprintf(out, "\tif len(errs.Errors) > 0 {\n\t\treturn errs\n\t}\n\n\treturn nil\n}\n")
+	return nil
+}
+
+// genStruct generate flags and bind code of struct fields, target is the path of struct like 'v.DB.'
+func (g *generator) genStruct(state *typeState, name string, target string, prefix string) error {
+	fields, err := g.fields(g.structs[name])
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		path := target + f.name
+		if tag, ok := f.tag.Lookup("arg"); ok {
+			if err := g.genArg(state, f, path, tag); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if nested, isPtr := g.nestedType(f.typ); nested != "" {
+			if !f.isNested() {
+				continue
+			}
+
+			nestedPrefix := prefix + f.flagName() + "-"
+			if f.embedded && f.tag.Get("cli") == "" {
+				nestedPrefix = prefix
+			}
+
+			if !isPtr {
+				if err := g.genStruct(state, nested, path+".", nestedPrefix); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			g.tmp++
+			tmp := fmt.Sprintf("tmp%d", g.tmp)
+			set := fmt.Sprintf("set%d", g.tmp)
+			fmt.Fprintf(state.bind, "\t{\n\tvar %s %s\n\t%s := false\n", tmp, nested, set)
+			state.sets = append(state.sets, set)
+			if err := g.genStruct(state, nested, tmp+".", nestedPrefix); err != nil {
+				return err
+			}
+
+			state.sets = state.sets[:len(state.sets)-1]
+			fmt.Fprintf(state.bind, "\tif %s {\n\t%s = &%s\n%s\t}\n\t}\n\n", set, path, tmp, g.setCode(state))
+			continue
+		}
+
+		if f.embedded {
+			return fmt.Errorf("embedded %s is not supported", f.name)
+		}
+
+		if err := g.genFlag(state, f, path, prefix+f.flagName()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// genFlag generate flag definition and bind code
+func (g *generator) genFlag(state *typeState, f field, path string, name string) error {
+	info, err := g.leafType(f.typ)
+	if err != nil {
+		return fmt.Errorf("field %s, %+v", f.name, err)
+	}
+
+	items := []string{fmt.Sprintf("Name: %q", name)}
+	for _, key := range []string{"short", "default", "param", "usage", "env"} {
+		value := f.tag.Get(key)
+		if value == "" {
+			continue
+		}
+
+		fieldName := strings.Title(key)
+		if key == "default" {
+			fieldName = "Value"
+		}
+
+		items = append(items, fmt.Sprintf("%s: %q", fieldName, value))
+	}
+
+	for _, key := range []string{"required", "secret", "hidden"} {
+		value := f.tag.Get(key)
+		if value == "" {
+			continue
+		}
+
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("bad tag %s of field %s, %+v", key, f.name, err)
+		}
+
+		if v {
+			items = append(items, fmt.Sprintf("%s: true", strings.Title(key)))
+		}
+	}
+
+	if info.kind == "slice" || info.kind == "map" {
+		items = append(items, "Multiple: true")
+	}
+
+	if enum := f.tag.Get("enum"); enum != "" {
+		quoted := make([]string, 0)
+		for _, e := range strings.Split(enum, ",") {
+			quoted = append(quoted, strconv.Quote(e))
+		}
+
+		items = append(items, fmt.Sprintf("Enum: []string{%s}", strings.Join(quoted, ", ")))
+	}
+
+	fmt.Fprintf(state.flags, "\t\t{%s},\n", strings.Join(items, ", "))
+
+	cond := "flag.Len() > 0"
+	if info.basic == "bool" && info.kind != "slice" && info.kind != "map" {
+		cond = "(flag.Len() > 0 || flag.Used())"
+	}
+
+	b := state.bind
+	fmt.Fprintf(b, "\tif flag := ctx.Flag(%q); flag != nil && %s {\n", name, cond)
+	fmt.Fprintf(b, "\tvalues := flag.GetList()\n")
+	if cond != "flag.Len() > 0" {
+		fmt.Fprintf(b, "\tif len(values) == 0 {\n\tvalues = []string{\"true\"}\n\t}\n\n")
+	}
+
+	errExpr := fmt.Sprintf("&cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, \",\"), Type: %q, Err: err}", info.src)
+	g.genAssign(state, info, path, errExpr)
+	fmt.Fprintf(b, "\t}\n\n")
+	return nil
+}
+
+// genArg generate bind code of positional arg
+func (g *generator) genArg(state *typeState, f field, path string, tag string) error {
+	info, err := g.leafType(f.typ)
+	if err != nil {
+		return fmt.Errorf("field %s, %+v", f.name, err)
+	}
+
+	name := tag
+	if _, err := strconv.Atoi(tag); err == nil || tag == "rest" || tag == "" {
+		name = kebabCase(f.name)
+	}
+
+	required, _ := strconv.ParseBool(f.tag.Get("required"))
+
+	var pos int
+	pos, state.next = argPosition(tag, state.next)
+	values := fmt.Sprintf("ctx.Args()[%d:%d]", pos, pos+1)
+	if pos == -1 {
+		pos = state.rest
+		values = fmt.Sprintf("ctx.Args()[%d:]", pos)
+	}
+
+	b := state.bind
+	fmt.Fprintf(b, "\tif ctx.NArg() > %d {\n\tvalues := %s\n", pos, values)
+	if tag == "rest" && info.kind == "basic" && info.basic == "string" {
+		fmt.Fprintf(b, "\tvalues = []string{strings.Join(values, \" \")}\n")
+		g.imports["strings"] = true
+	}
+
+	errExpr := fmt.Sprintf("&cli.InvalidValueError{Index: %d, Arg: %q, Value: strings.Join(values, \" \"), Type: %q, Err: err}", pos, name, info.src)
+	g.genAssign(state, info, path, errExpr)
+	if required {
+		fmt.Fprintf(b, "\t} else {\n\terrs.Errors = append(errs.Errors, &cli.InvalidValueError{Index: %d, Arg: %q, Err: cli.ErrMissingValue})\n", pos, name)
+	}
+
+	fmt.Fprintf(b, "\t}\n\n")
+	return nil
+}
+
+// genAssign generate code convert values to field
+func (g *generator) genAssign(state *typeState, info *typeInfo, path string, errExpr string) {
+	b := state.bind
+	sets := g.setCode(state)
+	if info.basic == "string" {
+		switch info.kind {
+		case "basic":
+			fmt.Fprintf(b, "\t%s = values[0]\n%s", path, sets)
+		case "ptr":
+			fmt.Fprintf(b, "\tx := values[0]\n\t%s = &x\n%s", path, sets)
+		case "slice":
+			fmt.Fprintf(b, "\t%s = append([]string(nil), values...)\n%s", path, sets)
+		case "map":
+			fmt.Fprintf(b, "\tm := make(map[string]string, len(values))\n\tvar err error\n")
+			fmt.Fprintf(b, "\tfor _, s := range values {\n\ti := strings.IndexAny(s, \"=:\")\n")
+			fmt.Fprintf(b, "\tif i == -1 {\n\terr = errors.New(\"map value must be split by '=' or ':'\")\n\tbreak\n\t}\n\n")
+			fmt.Fprintf(b, "\tm[s[:i]] = s[i+1:]\n\t}\n\n")
+			fmt.Fprintf(b, "\tif err != nil {\n\terrs.Errors = append(errs.Errors, %s)\n\t} else {\n\t%s = m\n%s\t}\n", errExpr, path, sets)
+			g.imports["errors"] = true
+			g.imports["strings"] = true
+		}
+
+		return
+	}
+
+	// errExpr use strings.Join
+	g.imports["strings"] = true
+	g.imports["strconv"] = true
+	parser := basics[info.basic]
+	cast := func(x string) string {
+		if parser.cast == "" {
+			return x
+		}
+
+		return fmt.Sprintf("%s(%s)", parser.cast, x)
+	}
+
+	switch info.kind {
+	case "basic", "ptr":
+		fmt.Fprintf(b, "\tif x, err := %s; err != nil {\n", fmt.Sprintf(parser.call, "values[0]"))
+		fmt.Fprintf(b, "\terrs.Errors = append(errs.Errors, %s)\n\t} else {\n", errExpr)
+		if info.kind == "basic" {
+			fmt.Fprintf(b, "\t%s = %s\n%s\t}\n", path, cast("x"), sets)
+		} else {
+			fmt.Fprintf(b, "\ty := %s\n\t%s = &y\n%s\t}\n", cast("x"), path, sets)
+		}
+	case "slice":
+		fmt.Fprintf(b, "\tlist := make([]%s, 0, len(values))\n\tvar err error\n", info.basic)
+		fmt.Fprintf(b, "\tfor _, s := range values {\n\tvar x %s\n", parser.parsed)
+		fmt.Fprintf(b, "\tif x, err = %s; err != nil {\n\tbreak\n\t}\n\n", fmt.Sprintf(parser.call, "s"))
+		fmt.Fprintf(b, "\tlist = append(list, %s)\n\t}\n\n", cast("x"))
+		fmt.Fprintf(b, "\tif err != nil {\n\terrs.Errors = append(errs.Errors, %s)\n\t} else {\n\t%s = list\n%s\t}\n", errExpr, path, sets)
+	case "map":
+		g.imports["errors"] = true
+		fmt.Fprintf(b, "\tm := make(map[string]%s, len(values))\n\tvar err error\n", info.basic)
+		fmt.Fprintf(b, "\tfor _, s := range values {\n\ti := strings.IndexAny(s, \"=:\")\n")
+		fmt.Fprintf(b, "\tif i == -1 {\n\terr = errors.New(\"map value must be split by '=' or ':'\")\n\tbreak\n\t}\n\n")
+		fmt.Fprintf(b, "\tvar x %s\n\tif x, err = %s; err != nil {\n\tbreak\n\t}\n\n", parser.parsed, fmt.Sprintf(parser.call, "s[i+1:]"))
+		fmt.Fprintf(b, "\tm[s[:i]] = %s\n\t}\n\n", cast("x"))
+		fmt.Fprintf(b, "\tif err != nil {\n\terrs.Errors = append(errs.Errors, %s)\n\t} else {\n\t%s = m\n%s\t}\n", errExpr, path, sets)
+	}
+}
+
+// setCode mark all enclosing pointer structs as set
+func (g *generator) setCode(state *typeState) string {
+	code := ""
+	for _, set := range state.sets {
+		code += fmt.Sprintf("\t%s = true\n", set)
+	}
+
+	return code
+}
+
+var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
+var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
+
+// kebabCase return xx-xx-xx, same as cli
+func kebabCase(str string) string {
+	snake := matchFirstCap.ReplaceAllString(str, "${1}-${2}")
+	snake = matchAllCap.ReplaceAllString(snake, "${1}-${2}")
+	return strings.ToLower(snake)
+}
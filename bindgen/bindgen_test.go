@@ -0,0 +1,100 @@
+package bindgen_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jeckbjy/cli"
+	"github.com/jeckbjy/cli/bindgen"
+	"github.com/jeckbjy/cli/bindgen/internal/sample"
+	"github.com/jeckbjy/cli/bindgen/internal/sample/strs"
+)
+
+// generated code of sample packages is compiled as part of this test
+func TestSync(t *testing.T) {
+	bindgen.CheckSync(t, bindgen.Config{Dir: "internal/sample", Types: []string{"All"}}, "sample_bind.go")
+	bindgen.CheckSync(t, bindgen.Config{Dir: "internal/sample/strs", Types: []string{"Strs"}}, "strs_bind.go")
+}
+
+// plain has the same fields as sample.All, without generated methods
+type plain sample.All
+
+// bindBoth bind args by generated code and by reflection
+func bindBoth(t *testing.T, args ...string) (sample.All, plain, error, error) {
+	var gen sample.All
+	var ref plain
+	var genErr, refErr error
+
+	app := cli.New()
+	genCmd := cli.NewCmd("gen", "", func(ctx *cli.Context) { genErr = ctx.BindE(&gen) })
+	genCmd.AddStructFlags(&sample.All{})
+	refCmd := cli.NewCmd("ref", "", func(ctx *cli.Context) { refErr = ctx.BindE(&ref) })
+	refCmd.AddStructFlags(&plain{})
+	app.AddCommands([]*cli.Command{genCmd, refCmd})
+
+	if err := app.RunArgs(append([]string{"t", "gen"}, args...)); err != nil {
+		t.Fatalf("run gen fail, %+v", err)
+	}
+
+	if err := app.RunArgs(append([]string{"t", "ref"}, args...)); err != nil {
+		t.Fatalf("run ref fail, %+v", err)
+	}
+
+	return gen, ref, genErr, refErr
+}
+
+func TestBindSameAsReflection(t *testing.T) {
+	cases := [][]string{
+		{"pods", "a", "b", "-v", "-c", "3", "--level=2", "--ratio=1.5", "--wait=2s", "--port=80", "--label=x",
+			"--ratios=1", "--ratios=2.5", "--files=a", "--files=b", "--tag=a=1", "--tag=b:2", "--labels=k=v",
+			"--db-port=5432", "--cache-host=redis", "--secret=s"},
+		{"pods"},
+		{},
+		{"pods", "-c", "x", "--ratio=q", "--wait=1", "--port=p", "--ratios=z", "--tag=a", "--labels=b", "--cache-port=y"},
+	}
+
+	for _, args := range cases {
+		gen, ref, genErr, refErr := bindBoth(t, args...)
+		if !reflect.DeepEqual(gen, sample.All(ref)) {
+			t.Errorf("args %v, generated %+v, reflection %+v", args, gen, ref)
+		}
+
+		if fmt.Sprint(genErr) != fmt.Sprint(refErr) {
+			t.Errorf("args %v, generated error %v, reflection error %v", args, genErr, refErr)
+		}
+	}
+}
+
+func TestFlagsSameAsReflection(t *testing.T) {
+	flags, err := cli.ParseFlags(&plain{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual((&sample.All{}).Flags(), flags) {
+		t.Errorf("generated flags differ from ParseFlags")
+	}
+}
+
+func TestRejectValidationTag(t *testing.T) {
+	_, err := bindgen.Generate(bindgen.Config{Dir: "testdata/invalid", Types: []string{"Invalid"}})
+	if err == nil {
+		t.Errorf("validation tag should be rejected")
+	}
+}
+
+func TestBindStrings(t *testing.T) {
+	var v strs.Strs
+	app := cli.New()
+	cmd := cli.NewCmd("s", "", func(ctx *cli.Context) { ctx.Bind(&v) })
+	cmd.AddStructFlags(&strs.Strs{})
+	app.AddCommands([]*cli.Command{cmd})
+	if err := app.RunArgs([]string{"t", "s", "--name=a", "--tags=x", "--tags=y", "--ptr=p"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Name != "a" || !reflect.DeepEqual(v.Tags, []string{"x", "y"}) || v.Ptr == nil || *v.Ptr != "p" {
+		t.Errorf("bind strings fail, %+v", v)
+	}
+}
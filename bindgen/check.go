@@ -0,0 +1,43 @@
+package bindgen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// CheckSync regenerate code of conf and compare with output file, used in test of package,
+// fail if struct is changed but go generate is not run
+// example:
+//
+//	func TestBindgen(t *testing.T) {
+//		bindgen.CheckSync(t, bindgen.Config{Types: []string{"CreateFlags"}}, "flags_bind.go")
+//	}
+func CheckSync(t testing.TB, conf Config, output string) {
+	t.Helper()
+	if conf.Dir == "" {
+		conf.Dir = "."
+	}
+
+	expect, err := Generate(conf)
+	if err != nil {
+		t.Fatalf("bindgen generate fail, %+v", err)
+		return
+	}
+
+	path := output
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(conf.Dir, path)
+	}
+
+	actual, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("bindgen read %s fail, %+v", path, err)
+		return
+	}
+
+	if !bytes.Equal(expect, actual) {
+		t.Errorf("%s is out of date, please run go generate", path)
+	}
+}
@@ -0,0 +1,236 @@
+// Code generated by clibindgen. DO NOT EDIT.
+
+package sample
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeckbjy/cli"
+)
+
+// Flags return flags generated from All
+func (v *All) Flags() []*cli.Flag {
+	return []*cli.Flag{
+		{Name: "verbose", Short: "v"},
+		{Name: "count", Short: "c"},
+		{Name: "level", Enum: []string{"1", "2", "3"}},
+		{Name: "ratio"},
+		{Name: "wait", Env: "SAMPLE_WAIT"},
+		{Name: "port", Usage: "the port"},
+		{Name: "label"},
+		{Name: "ratios", Multiple: true},
+		{Name: "files", Multiple: true},
+		{Name: "tag", Multiple: true},
+		{Name: "labels", Multiple: true},
+		{Name: "db-host", Value: "localhost"},
+		{Name: "db-port"},
+		{Name: "cache-host", Value: "localhost"},
+		{Name: "cache-port"},
+		{Name: "secret", Secret: true, Hidden: true},
+	}
+}
+
+// Bind bind flags and args of ctx to All without reflection
+func (v *All) Bind(ctx *cli.Context) error {
+	errs := &cli.BindError{}
+	if flag := ctx.Flag("verbose"); flag != nil && (flag.Len() > 0 || flag.Used()) {
+		values := flag.GetList()
+		if len(values) == 0 {
+			values = []string{"true"}
+		}
+
+		if x, err := strconv.ParseBool(values[0]); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "bool", Err: err})
+		} else {
+			v.Common.Verbose = x
+		}
+	}
+
+	if ctx.NArg() > 0 {
+		values := ctx.Args()[0:1]
+		v.Resource = values[0]
+	} else {
+		errs.Errors = append(errs.Errors, &cli.InvalidValueError{Index: 0, Arg: "resource", Err: cli.ErrMissingValue})
+	}
+
+	if ctx.NArg() > 1 {
+		values := ctx.Args()[1:]
+		v.Names = append([]string(nil), values...)
+	}
+
+	if flag := ctx.Flag("count"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		if x, err := strconv.ParseInt(values[0], 10, 0); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "int", Err: err})
+		} else {
+			v.Count = int(x)
+		}
+	}
+
+	if flag := ctx.Flag("level"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		if x, err := strconv.ParseUint(values[0], 10, 8); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "uint8", Err: err})
+		} else {
+			v.Level = uint8(x)
+		}
+	}
+
+	if flag := ctx.Flag("ratio"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		if x, err := strconv.ParseFloat(values[0], 32); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "float32", Err: err})
+		} else {
+			v.Ratio = float32(x)
+		}
+	}
+
+	if flag := ctx.Flag("wait"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		if x, err := time.ParseDuration(values[0]); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "time.Duration", Err: err})
+		} else {
+			v.Wait = x
+		}
+	}
+
+	if flag := ctx.Flag("port"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		if x, err := strconv.ParseInt(values[0], 10, 0); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "*int", Err: err})
+		} else {
+			y := int(x)
+			v.Port = &y
+		}
+	}
+
+	if flag := ctx.Flag("label"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		x := values[0]
+		v.Label = &x
+	}
+
+	if flag := ctx.Flag("ratios"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		list := make([]float64, 0, len(values))
+		var err error
+		for _, s := range values {
+			var x float64
+			if x, err = strconv.ParseFloat(s, 64); err != nil {
+				break
+			}
+
+			list = append(list, x)
+		}
+
+		if err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "[]float64", Err: err})
+		} else {
+			v.Ratios = list
+		}
+	}
+
+	if flag := ctx.Flag("files"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		v.Files = append([]string(nil), values...)
+	}
+
+	if flag := ctx.Flag("tag"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		m := make(map[string]int, len(values))
+		var err error
+		for _, s := range values {
+			i := strings.IndexAny(s, "=:")
+			if i == -1 {
+				err = errors.New("map value must be split by '=' or ':'")
+				break
+			}
+
+			var x int64
+			if x, err = strconv.ParseInt(s[i+1:], 10, 0); err != nil {
+				break
+			}
+
+			m[s[:i]] = int(x)
+		}
+
+		if err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "map[string]int", Err: err})
+		} else {
+			v.Tags = m
+		}
+	}
+
+	if flag := ctx.Flag("labels"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		m := make(map[string]string, len(values))
+		var err error
+		for _, s := range values {
+			i := strings.IndexAny(s, "=:")
+			if i == -1 {
+				err = errors.New("map value must be split by '=' or ':'")
+				break
+			}
+
+			m[s[:i]] = s[i+1:]
+		}
+
+		if err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "map[string]string", Err: err})
+		} else {
+			v.Labels = m
+		}
+	}
+
+	if flag := ctx.Flag("db-host"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		v.DB.Host = values[0]
+	}
+
+	if flag := ctx.Flag("db-port"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		if x, err := strconv.ParseInt(values[0], 10, 0); err != nil {
+			errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "int", Err: err})
+		} else {
+			v.DB.Port = int(x)
+		}
+	}
+
+	{
+		var tmp1 DB
+		set1 := false
+		if flag := ctx.Flag("cache-host"); flag != nil && flag.Len() > 0 {
+			values := flag.GetList()
+			tmp1.Host = values[0]
+			set1 = true
+		}
+
+		if flag := ctx.Flag("cache-port"); flag != nil && flag.Len() > 0 {
+			values := flag.GetList()
+			if x, err := strconv.ParseInt(values[0], 10, 0); err != nil {
+				errs.Errors = append(errs.Errors, &cli.InvalidValueError{Flag: flag.Name, Value: strings.Join(values, ","), Type: "int", Err: err})
+			} else {
+				tmp1.Port = int(x)
+				set1 = true
+			}
+		}
+
+		if set1 {
+			v.Cache = &tmp1
+		}
+	}
+
+	if flag := ctx.Flag("secret"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		v.Secret = values[0]
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// Code generated by clibindgen. DO NOT EDIT.
+
+package strs
+
+import (
+	"github.com/jeckbjy/cli"
+)
+
+// Flags return flags generated from Strs
+func (v *Strs) Flags() []*cli.Flag {
+	return []*cli.Flag{
+		{Name: "name"},
+		{Name: "tags", Multiple: true},
+		{Name: "ptr"},
+	}
+}
+
+// Bind bind flags and args of ctx to Strs without reflection
+func (v *Strs) Bind(ctx *cli.Context) error {
+	errs := &cli.BindError{}
+	if flag := ctx.Flag("name"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		v.Name = values[0]
+	}
+
+	if flag := ctx.Flag("tags"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		v.Tags = append([]string(nil), values...)
+	}
+
+	if flag := ctx.Flag("ptr"); flag != nil && flag.Len() > 0 {
+		values := flag.GetList()
+		x := values[0]
+		v.Ptr = &x
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+// Package strs struct of only string kinds, generated code must not import strings
+package strs
+
+//go:generate go run ../../../../cmd/clibindgen -type=Strs -output=strs_bind.go
+
+// Strs string, slice and pointer of string
+type Strs struct {
+	Name string
+	Tags []string
+	Ptr  *string
+}
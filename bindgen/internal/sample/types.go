@@ -0,0 +1,38 @@
+// Package sample structs covering all kinds supported by bindgen, used by test
+package sample
+
+import "time"
+
+//go:generate go run ../../../cmd/clibindgen -type=All -output=sample_bind.go
+
+// DB nested struct
+type DB struct {
+	Host string `default:"localhost"`
+	Port int
+}
+
+// Common embedded struct
+type Common struct {
+	Verbose bool `short:"v"`
+}
+
+// All cover basic, ptr, slice, map, nested, ptr-nested, arg and rest
+type All struct {
+	Common
+	Resource string   `arg:"resource" required:"true"`
+	Names    []string `arg:"rest"`
+	Count    int      `short:"c"`
+	Level    uint8    `enum:"1,2,3"`
+	Ratio    float32
+	Wait     time.Duration `env:"SAMPLE_WAIT"`
+	Port     *int          `usage:"the port"`
+	Label    *string
+	Ratios   []float64
+	Files    []string
+	Tags     map[string]int `cli:"tag"`
+	Labels   map[string]string
+	DB       DB  `cli:"db"`
+	Cache    *DB `cli:"cache"`
+	Ignored  DB
+	Secret   string `secret:"true" hidden:"true"`
+}
@@ -0,0 +1,6 @@
+package invalid
+
+// Invalid has validation tag
+type Invalid struct {
+	Port int `min:"1"`
+}
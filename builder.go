@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// CommandBuilder build command tree fluently, panic as soon as definition is invalid,
+// such as duplicate flag, conflicting alias or bad argument order
+// example:
+//
+//	app.Command("create").
+//		Group("Basic").
+//		Short("Create a resource from a file or from stdin").
+//		Flag(&cli.Flag{Name: "filename", Short: "f", Param: "path", Required: true}).
+//		Arg(&cli.Arg{Name: "resource"}).
+//		Sub("deployment", func(b *cli.CommandBuilder) {
+//			b.Flag(&cli.Flag{Name: "image"}).ActionE(onCreateDeployment)
+//		}).
+//		Action(onCreate)
+type CommandBuilder struct {
+	app   *App
+	chain []*Command // from root to the command
+	cmd   *Command
+}
+
+// Command return builder of command under root, create if not exist
+func (app *App) Command(name string) *CommandBuilder {
+	b := &CommandBuilder{app: app, chain: []*Command{app.root}, cmd: app.root}
+	return b.sub(name)
+}
+
+// Cmd return the built command
+func (b *CommandBuilder) Cmd() *Command {
+	return b.cmd
+}
+
+// sub return builder of sub command, create if not exist
+func (b *CommandBuilder) sub(name string) *CommandBuilder {
+	if name == "help" {
+		b.fail("command name 'help' is reserved")
+	}
+
+	cmd := b.cmd.findSub(name)
+	if cmd == nil {
+		cmd = &Command{Name: name}
+		b.cmd.AddSub(cmd)
+	} else if cmd.Name != name {
+		b.fail("command %q conflict with alias of %q", name, cmd.Name)
+	}
+
+	chain := make([]*Command, 0, len(b.chain)+1)
+	chain = append(chain, b.chain...)
+	chain = append(chain, cmd)
+	return &CommandBuilder{app: b.app, chain: chain, cmd: cmd}
+}
+
+// fail panic with path of command
+func (b *CommandBuilder) fail(format string, args ...interface{}) {
+	panic(fmt.Errorf("command '%s': %s", b.app.commandPath(b.chain), fmt.Sprintf(format, args...)))
+}
+
+// Sub add or extend sub command, build it in fn
+func (b *CommandBuilder) Sub(name string, fn func(sub *CommandBuilder)) *CommandBuilder {
+	sub := b.sub(name)
+	if fn != nil {
+		fn(sub)
+	}
+
+	return b
+}
+
+// Group set group name
+func (b *CommandBuilder) Group(group string) *CommandBuilder {
+	b.cmd.Group = group
+	return b
+}
+
+// Short set short describe
+func (b *CommandBuilder) Short(short string) *CommandBuilder {
+	b.cmd.Short = short
+	return b
+}
+
+// Long set long describe
+func (b *CommandBuilder) Long(long string) *CommandBuilder {
+	b.cmd.Long = long
+	return b
+}
+
+// Header set help header
+func (b *CommandBuilder) Header(header string) *CommandBuilder {
+	b.cmd.Header = header
+	return b
+}
+
+// Footer set help footer
+func (b *CommandBuilder) Footer(footer string) *CommandBuilder {
+	b.cmd.Footer = footer
+	return b
+}
+
+// Alias add alias, panic if conflict with sibling commands
+func (b *CommandBuilder) Alias(names ...string) *CommandBuilder {
+	parent := b.chain[len(b.chain)-2]
+	for _, name := range names {
+		if name == "" {
+			b.fail("empty alias")
+		}
+
+		if other := parent.findSub(name); other != nil && other != b.cmd {
+			b.fail("alias %q conflict with command %q", name, other.Name)
+		}
+
+		b.cmd.Alias = append(b.cmd.Alias, name)
+	}
+
+	return b
+}
+
+// Hidden hide command from help and completion
+func (b *CommandBuilder) Hidden() *CommandBuilder {
+	b.cmd.Hidden = true
+	return b
+}
+
+// Deprecated mark command deprecated with notice
+func (b *CommandBuilder) Deprecated(notice string) *CommandBuilder {
+	b.cmd.Deprecated = notice
+	return b
+}
+
+// Timeout set deadline of Context.Ctx()
+func (b *CommandBuilder) Timeout(timeout time.Duration) *CommandBuilder {
+	b.cmd.Timeout = timeout
+	return b
+}
+
+// Flag add flags, panic if name is empty, or name or short is reserved or used by the command chain or sub commands
+func (b *CommandBuilder) Flag(flags ...*Flag) *CommandBuilder {
+	used := make(map[string]bool)
+	for _, cmd := range b.chain {
+		addFlagKeys(used, cmd.Flags)
+	}
+
+	walkSubs(b.cmd, func(sub *Command) {
+		addFlagKeys(used, sub.Flags)
+	})
+
+	for _, flag := range flags {
+		if flag.Name == "" {
+			b.fail("flag name is empty")
+		}
+
+		if len(flag.Short) > 1 {
+			b.fail("short of flag --%s must be one char", flag.Name)
+		}
+
+		for _, key := range []string{flag.Name, flag.Short} {
+			if key == "help" || key == "h" {
+				b.fail("flag %q is reserved", key)
+			}
+
+			if key != "" && used[key] {
+				b.fail("flag %q is already used", key)
+			}
+		}
+
+		if flag.Value != "" && len(flag.Enum) > 0 && !flag.inEnum(flag.Value) {
+			b.fail("default %q of flag --%s is not in enum", flag.Value, flag.Name)
+		}
+
+		addFlagKeys(used, []*Flag{flag})
+		b.cmd.Flags = append(b.cmd.Flags, flag)
+	}
+
+	return b
+}
+
+// StructFlags add flags generated from tagged struct, see Command.AddStructFlags
+func (b *CommandBuilder) StructFlags(v interface{}) *CommandBuilder {
+	if src, ok := v.(FlagSource); ok {
+		return b.Flag(src.Flags()...)
+	}
+
+	flags, err := ParseFlags(v)
+	if err != nil {
+		b.fail("%+v", err)
+	}
+
+	return b.Flag(flags...)
+}
+
+// Arg add positional args, panic if name is empty or duplicate,
+// required arg after optional arg or any arg after rest arg
+func (b *CommandBuilder) Arg(args ...*Arg) *CommandBuilder {
+	for _, arg := range args {
+		if arg.Name == "" {
+			b.fail("arg name is empty")
+		}
+
+		for _, prev := range b.cmd.Args {
+			if prev.Name == arg.Name {
+				b.fail("arg <%s> is duplicate", arg.Name)
+			}
+
+			if prev.Rest {
+				b.fail("arg <%s> is after rest arg <%s>", arg.Name, prev.Name)
+			}
+
+			if arg.Required && !prev.Required {
+				b.fail("required arg <%s> is after optional arg <%s>", arg.Name, prev.Name)
+			}
+		}
+
+		b.cmd.Args = append(b.cmd.Args, arg)
+	}
+
+	return b
+}
+
+// Action set action, panic if action is already set
+func (b *CommandBuilder) Action(action Action) *CommandBuilder {
+	if b.cmd.hasAction() {
+		b.fail("action is already set")
+	}
+
+	b.cmd.Run = action
+	return b
+}
+
+// ActionE set error-returning action, panic if action is already set
+func (b *CommandBuilder) ActionE(action ActionE) *CommandBuilder {
+	if b.cmd.hasAction() {
+		b.fail("action is already set")
+	}
+
+	b.cmd.RunE = action
+	return b
+}
+
+// Use add middlewares
+func (b *CommandBuilder) Use(middlewares ...Action) *CommandBuilder {
+	b.cmd.Use(middlewares...)
+	return b
+}
+
+// PreRun set hook run before action
+func (b *CommandBuilder) PreRun(hook ActionE) *CommandBuilder {
+	b.cmd.PreRun = hook
+	return b
+}
+
+// PostRun set hook run after action
+func (b *CommandBuilder) PostRun(hook ActionE) *CommandBuilder {
+	b.cmd.PostRun = hook
+	return b
+}
+
+// Finally set hook run after action even on error
+func (b *CommandBuilder) Finally(hook Action) *CommandBuilder {
+	b.cmd.Finally = hook
+	return b
+}
+
+func addFlagKeys(keys map[string]bool, flags []*Flag) {
+	for _, f := range flags {
+		keys[f.Name] = true
+		if f.Short != "" {
+			keys[f.Short] = true
+		}
+	}
+}
+
+// walkSubs call fn with all descendants of cmd
+func walkSubs(cmd *Command, fn func(sub *Command)) {
+	for _, sub := range cmd.Subs {
+		fn(sub)
+		walkSubs(sub, fn)
+	}
+}
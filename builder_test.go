@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// expectPanic check fn panic with message containing msg
+func expectPanic(t *testing.T, msg string, fn func()) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("should panic with %q", msg)
+		} else if !strings.Contains(fmt.Sprint(r), msg) {
+			t.Errorf("should panic with %q, got %v", msg, r)
+		}
+	}()
+
+	fn()
+}
+
+func TestBuilderTree(t *testing.T) {
+	var ran string
+	app := New()
+	app.Command("create").
+		Alias("new").
+		Flag(&Flag{Name: "filename", Short: "f"}).
+		Sub("deployment", func(b *CommandBuilder) {
+			b.Flag(&Flag{Name: "image"}).Action(func(ctx *Context) {
+				ran = ctx.FlagStrOr("filename", "") + " " + ctx.FlagStrOr("image", "")
+			})
+		})
+
+	// extend existing command
+	app.Command("create").Short("Create a resource")
+
+	if err := app.RunArgs([]string{"t", "new", "deployment", "-f", "a.yaml", "--image=nginx"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ran != "a.yaml nginx" {
+		t.Errorf("run fail, %q", ran)
+	}
+
+	if create := app.Root().findSub("create"); create.Short != "Create a resource" || len(create.Subs) != 1 {
+		t.Errorf("command should be extended, not created again")
+	}
+}
+
+func TestBuilderDuplicate(t *testing.T) {
+	app := New()
+	app.AddFlags([]*Flag{{Name: "verbose", Short: "v"}})
+	app.Command("get").Alias("g").Flag(&Flag{Name: "output", Short: "o"})
+	app.Command("create").Sub("deployment", func(b *CommandBuilder) {
+		b.Flag(&Flag{Name: "image"})
+	})
+
+	expectPanic(t, "command name 'help' is reserved", func() { app.Command("help") })
+	expectPanic(t, `command "g" conflict with alias of "get"`, func() { app.Command("g") })
+	expectPanic(t, `alias "get" conflict with command "get"`, func() { app.Command("create").Alias("get") })
+	expectPanic(t, `alias "g" conflict with command "get"`, func() { app.Command("create").Alias("g") })
+	expectPanic(t, "empty alias", func() { app.Command("create").Alias("") })
+	expectPanic(t, "action is already set", func() {
+		app.Command("get").Action(func(ctx *Context) {}).ActionE(func(ctx *Context) error { return nil })
+	})
+
+	expectPanic(t, "flag name is empty", func() { app.Command("get").Flag(&Flag{}) })
+	expectPanic(t, "must be one char", func() { app.Command("get").Flag(&Flag{Name: "all", Short: "al"}) })
+	expectPanic(t, `flag "h" is reserved`, func() { app.Command("get").Flag(&Flag{Name: "host", Short: "h"}) })
+	expectPanic(t, `flag "verbose" is already used`, func() { app.Command("get").Flag(&Flag{Name: "verbose"}) })
+	expectPanic(t, `flag "o" is already used`, func() { app.Command("get").Flag(&Flag{Name: "out", Short: "o"}) })
+	expectPanic(t, `flag "image" is already used`, func() { app.Command("create").Flag(&Flag{Name: "image"}) })
+	expectPanic(t, `flag "x" is already used`, func() {
+		app.Command("get").Flag(&Flag{Name: "x1", Short: "x"}, &Flag{Name: "x2", Short: "x"})
+	})
+	expectPanic(t, "is not in enum", func() {
+		app.Command("get").Flag(&Flag{Name: "format", Value: "xml", Enum: []string{"json", "yaml"}})
+	})
+
+	// the same flag is allowed in sibling commands
+	app.Command("delete").Flag(&Flag{Name: "output", Short: "o"})
+	expectPanic(t, "create deployment': flag \"image\" is already used", func() {
+		app.Command("create").Sub("deployment", func(b *CommandBuilder) { b.Flag(&Flag{Name: "image"}) })
+	})
+}
+
+func TestBuilderArgOrder(t *testing.T) {
+	app := New()
+	app.Command("get").Arg(&Arg{Name: "resource", Required: true}, &Arg{Name: "name"})
+
+	expectPanic(t, "arg name is empty", func() { app.Command("get").Arg(&Arg{}) })
+	expectPanic(t, "arg <resource> is duplicate", func() { app.Command("get").Arg(&Arg{Name: "resource"}) })
+	expectPanic(t, "required arg <label> is after optional arg <name>", func() {
+		app.Command("get").Arg(&Arg{Name: "label", Required: true})
+	})
+
+	app.Command("exec").Arg(&Arg{Name: "pod"}, &Arg{Name: "command", Rest: true})
+	expectPanic(t, "arg <env> is after rest arg <command>", func() { app.Command("exec").Arg(&Arg{Name: "env"}) })
+}
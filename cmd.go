@@ -1,8 +1,20 @@
 package cli
 
+import (
+	"strings"
+	"time"
+)
+
 // Action command callback
 type Action func(ctx *Context)
 
+// ActionE command callback return error, error will abort command chain
+type ActionE func(ctx *Context) error
+
+// NotFoundHandler called with unmatched token and remaining args when sub command not found,
+// flags before the token are parsed into ctx, call ctx.AbortWithError to return error from RunArgs
+type NotFoundHandler func(ctx *Context, name string, args []string)
+
 // CommandGroup command set
 type CommandGroup struct {
 	Name string
@@ -18,9 +30,41 @@ type Command struct {
 	Header string
 	Footer string
 	Run    Action
+	RunE   ActionE
 	Flags  []*Flag
 	Subs   []*Command
 	Alias  []string
+	// Hidden command can still be called, but not show in help and completion
+	Hidden bool
+	// Deprecated notice, print before run if not empty, support $key translate
+	Deprecated string
+	// NotFound handle unknown sub command, otherwise token becomes param
+	NotFound NotFoundHandler
+	// Timeout set deadline of Context.Ctx(), inherited by sub commands
+	Timeout time.Duration
+	// Middlewares wrap execution of command chain, call ctx.Next() to continue
+	Middlewares []Action
+	// PreRun and PostRun run around the action when command is the leaf
+	PreRun  ActionE
+	PostRun ActionE
+	// Finally run after the leaf command, even on abort, error or panic
+	Finally Action
+	// Persistent hooks are inherited by sub commands, run from root to leaf before,
+	// and from leaf to root after
+	PersistentPreRun  ActionE
+	PersistentPostRun ActionE
+	PersistentFinally Action
+	// Args declared positional arguments, optional, used by help and required check
+	Args []*Arg
+}
+
+// NewCmdE create command with error-returning action
+func NewCmdE(name string, group string, action ActionE) *Command {
+	return &Command{
+		Name:  name,
+		Group: group,
+		RunE:  action,
+	}
 }
 
 // NewCmd create command
@@ -56,6 +100,53 @@ func (cmd *Command) findSub(name string) *Command {
 	return nil
 }
 
+// findSubByPrefix return the only visible sub command whose name or alias start with prefix,
+// candidates are returned if more than one command matched
+func (cmd *Command) findSubByPrefix(prefix string) (*Command, []string) {
+	var found []*Command
+	var names []string
+	for _, sub := range cmd.Subs {
+		if sub.Hidden || sub.Name == AppCommandName {
+			continue
+		}
+
+		if strings.HasPrefix(sub.Name, prefix) {
+			found = append(found, sub)
+			names = append(names, sub.Name)
+			continue
+		}
+
+		for _, alias := range sub.Alias {
+			if strings.HasPrefix(alias, prefix) {
+				found = append(found, sub)
+				names = append(names, sub.Name)
+				break
+			}
+		}
+	}
+
+	if len(found) == 1 {
+		return found[0], nil
+	}
+
+	return nil, names
+}
+
+// hasAction return true if Run or RunE is set
+func (cmd *Command) hasAction() bool {
+	return cmd.Run != nil || cmd.RunE != nil
+}
+
+// IsDeprecated return true if command is marked deprecated
+func (cmd *Command) IsDeprecated() bool {
+	return cmd.Deprecated != ""
+}
+
+// Use add middlewares, run from root to leaf before commands
+func (cmd *Command) Use(middlewares ...Action) {
+	cmd.Middlewares = append(cmd.Middlewares, middlewares...)
+}
+
 func (cmd *Command) AddSub(sub *Command) {
 	cmd.Subs = append(cmd.Subs, sub)
 }
@@ -64,6 +155,10 @@ func (cmd *Command) AddSub(sub *Command) {
 func (cmd *Command) MaxSubNameLen() int {
 	length := 0
 	for _, sub := range cmd.Subs {
+		if sub.Hidden {
+			continue
+		}
+
 		if len(sub.Name) > length {
 			length = len(sub.Name)
 		}
@@ -78,8 +173,8 @@ func (cmd *Command) SubCommandGroups() []*CommandGroup {
 	global := &CommandGroup{}
 	for _, sub := range cmd.Subs {
 		var group *CommandGroup
-		// ignore help
-		if sub.Name == "help" {
+		// ignore help and hidden
+		if sub.Name == "help" || sub.Hidden {
 			continue
 		}
 
@@ -0,0 +1,49 @@
+// clibindgen generate Flags and Bind methods of tagged structs without reflection,
+// usage in source file:
+//
+//	//go:generate go run github.com/jeckbjy/cli/cmd/clibindgen -type=CreateFlags,GetFlags
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeckbjy/cli/bindgen"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct names, required")
+	output := flag.String("output", "", "output file name, default <first type>_bind.go")
+	dir := flag.String("dir", ".", "package directory")
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conf := bindgen.Config{Dir: *dir, Types: strings.Split(*typeNames, ",")}
+	src, err := bindgen.Generate(conf)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clibindgen: %+v\n", err)
+		os.Exit(1)
+	}
+
+	name := *output
+	if name == "" {
+		name = strings.ToLower(conf.Types[0]) + "_bind.go"
+	}
+
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(*dir, name)
+	}
+
+	if err := ioutil.WriteFile(name, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "clibindgen: %+v\n", err)
+		os.Exit(1)
+	}
+}
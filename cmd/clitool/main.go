@@ -0,0 +1,59 @@
+// clitool helper of cli projects
+//
+//	clitool new <name> --dir=cmds --group=Beginner --short="Create a resource" --flag=dry-run:bool --flag=output,o:string=yaml
+//	clitool new rollout status --dir=cmds --short="Show the status of the rollout"
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jeckbjy/cli"
+	"github.com/jeckbjy/cli/scaffold"
+)
+
+func main() {
+	app := cli.New()
+	app.AddHeader("clitool helper of cli projects\n")
+
+	app.Command("new").
+		Short("Generate new command following the layout of examples/kubectl/cmds").
+		Arg(&cli.Arg{Name: "name", Usage: "command name, like 'api-resources', or path of nested command, like 'rollout status'", Required: true, Rest: true}).
+		Flag(
+			&cli.Flag{Name: "dir", Short: "d", Param: "path", Value: ".", Usage: "package dir of commands"},
+			&cli.Flag{Name: "group", Short: "g", Value: "Other", Usage: "group of command"},
+			&cli.Flag{Name: "short", Short: "s", Usage: "short describe"},
+			&cli.Flag{Name: "long", Short: "l", Usage: "long describe"},
+			&cli.Flag{Name: "flag", Short: "f", Param: "spec", Multiple: true, Usage: "flag of command, like name[,short]:type[=default]"},
+		).
+		ActionE(onNew)
+
+	app.Run()
+}
+
+func onNew(ctx *cli.Context) error {
+	conf := scaffold.Config{
+		Dir:   ctx.FlagStr("dir"),
+		Name:  strings.Join(ctx.Args(), " "),
+		Group: ctx.FlagStr("group"),
+		Short: ctx.FlagStr("short"),
+		Long:  ctx.FlagStr("long"),
+	}
+
+	for _, str := range ctx.FlagList("flag") {
+		flag, err := scaffold.ParseFlag(str)
+		if err != nil {
+			return err
+		}
+
+		conf.Flags = append(conf.Flags, flag)
+	}
+
+	path, err := scaffold.Generate(conf)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out(), "create %s\n", path)
+	return nil
+}
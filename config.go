@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// config git style config file, comments are dropped when saved
+// example:
+// [alias]
+// co = checkout -b
+type config struct {
+	sections map[string]map[string]string
+}
+
+// loadConfig load config from file, return empty config if file not exist
+func loadConfig(path string) (*config, error) {
+	conf := &config{sections: make(map[string]map[string]string)}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return conf, nil
+		}
+
+		return nil, err
+	}
+	defer file.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("bad config %s:%d, %s", path, lineNo, line)
+			}
+
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		index := strings.IndexByte(line, '=')
+		if index == -1 {
+			return nil, fmt.Errorf("bad config %s:%d, %s", path, lineNo, line)
+		}
+
+		conf.Set(section, strings.TrimSpace(line[:index]), strings.TrimSpace(line[index+1:]))
+	}
+
+	return conf, scanner.Err()
+}
+
+// Get return value of key in section
+func (c *config) Get(section string, key string) string {
+	return c.sections[section][key]
+}
+
+// Set value of key in section
+func (c *config) Set(section string, key string, value string) {
+	values := c.sections[section]
+	if values == nil {
+		values = make(map[string]string)
+		c.sections[section] = values
+	}
+
+	values[key] = value
+}
+
+// Del remove key in section, return false if not exist
+func (c *config) Del(section string, key string) bool {
+	values := c.sections[section]
+	if _, ok := values[key]; !ok {
+		return false
+	}
+
+	delete(values, key)
+	return true
+}
+
+// Keys return sorted keys of section
+func (c *config) Keys(section string) []string {
+	keys := make([]string, 0, len(c.sections[section]))
+	for key := range c.sections[section] {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// save write config to file, sections and keys are sorted
+func (c *config) save(path string) error {
+	names := make([]string, 0, len(c.sections))
+	for name := range c.sections {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	builder := strings.Builder{}
+	for _, name := range names {
+		if len(c.sections[name]) == 0 {
+			continue
+		}
+
+		if name != "" {
+			builder.WriteString(fmt.Sprintf("[%s]\n", name))
+		}
+
+		for _, key := range c.Keys(name) {
+			builder.WriteString(fmt.Sprintf("%s = %s\n", key, c.sections[name][key]))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, []byte(builder.String()), 0644)
+}
@@ -1,11 +1,9 @@
 package cli
 
 import (
-	"fmt"
+	"io"
 	"math"
-	"reflect"
-	"regexp"
-	"strconv"
+	"time"
 )
 
 const abortIndex int = math.MaxInt32
@@ -15,9 +13,18 @@ type Context struct {
 	app   *App                   // the app
 	args  []string               // all raw args
 	cmds  []*Command             // all command chain
+	hands []Action               // middlewares and commands to run
 	flags map[string]*Flag       // all flag map
+	all   map[string]*Flag       // flags of command chain by name and short
 	datas map[string]interface{} // dynamic datas
 	index int                    // use for call command
+	err   error                  // first error of command chain
+	argv  []string               // command line after expanded
+	opts  []string               // option args before unknown command, forwarded to plugin
+	goctx                        // lazily created context.Context
+	in    io.Reader              // input stream
+	out   io.Writer              // output stream
+	errs  io.Writer              // error stream
 }
 
 func newContext(app *App, args []string, cmds []*Command, flags map[string]*Flag) *Context {
@@ -27,6 +34,9 @@ func newContext(app *App, args []string, cmds []*Command, flags map[string]*Flag
 		cmds:  cmds,
 		flags: flags,
 		index: -1,
+		in:    app.In,
+		out:   app.Out,
+		errs:  app.Err,
 	}
 }
 
@@ -34,6 +44,36 @@ func (c *Context) App() *App {
 	return c.app
 }
 
+// Argv return command line args after expanded, program name not included
+func (c *Context) Argv() []string {
+	return c.argv
+}
+
+// In return input stream
+func (c *Context) In() io.Reader {
+	return c.in
+}
+
+// Out return output stream
+func (c *Context) Out() io.Writer {
+	return c.out
+}
+
+// ErrOut return error stream
+func (c *Context) ErrOut() io.Writer {
+	return c.errs
+}
+
+// SetOut replace output stream, such as wrap by pager or colorizer in middleware
+func (c *Context) SetOut(w io.Writer) {
+	c.out = w
+}
+
+// SetErrOut replace error stream
+func (c *Context) SetErrOut(w io.Writer) {
+	c.errs = w
+}
+
 func (c *Context) CommandList() []*Command {
 	return c.cmds
 }
@@ -61,35 +101,44 @@ func (c *Context) NArg() int {
 	return len(c.args)
 }
 
-// Arg return arg by index
+// Args return all positional args
+func (c *Context) Args() []string {
+	return c.args
+}
+
+// Arg return arg by index, panic with InvalidValueError if out of range
 func (c *Context) Arg(i int) string {
-	// add offset?
-	return c.args[i]
+	return must(c.ArgE(i)).(string)
 }
 
 // ArgBool return bool arg by index
 func (c *Context) ArgBool(i int) bool {
-	return strToBool(c.Arg(i))
+	return must(c.ArgBoolE(i)).(bool)
 }
 
 // ArgInt return integer arg by index
 func (c *Context) ArgInt(i int) int {
-	return strToInt(c.Arg(i))
+	return must(c.ArgIntE(i)).(int)
 }
 
 // ArgUint return uint arg by index
 func (c *Context) ArgUint(i int) uint {
-	return strToUint(c.Arg(i))
+	return must(c.ArgUintE(i)).(uint)
 }
 
 // ArgF32 return float32 arg
 func (c *Context) ArgF32(i int) float32 {
-	return strToF32(c.Arg(i))
+	return must(c.ArgF32E(i)).(float32)
 }
 
 // ArgF64 return float64 arg
 func (c *Context) ArgF64(i int) float64 {
-	return strToF64(c.Arg(i))
+	return must(c.ArgF64E(i)).(float64)
+}
+
+// ArgDuration return time.Duration arg
+func (c *Context) ArgDuration(i int) time.Duration {
+	return must(c.ArgDurationE(i)).(time.Duration)
 }
 
 //////////////////////////////////////////////
@@ -116,29 +165,34 @@ func (c *Context) FlagStr(key string) string {
 	return ""
 }
 
-// FlagInt return int flag
+// FlagInt return int flag, 0 if not set, panic with InvalidValueError if invalid
 func (c *Context) FlagInt(key string) int {
-	return strToInt(c.FlagStr(key))
+	return must(c.FlagIntE(key)).(int)
 }
 
 // FlagUint return uint flag
 func (c *Context) FlagUint(key string) uint {
-	return strToUint(c.FlagStr(key))
+	return must(c.FlagUintE(key)).(uint)
 }
 
-// FlagBool return bool flag
+// FlagBool return bool flag, true if flag appear without value
 func (c *Context) FlagBool(key string) bool {
-	return strToBool(c.FlagStr(key))
+	return must(c.FlagBoolE(key)).(bool)
 }
 
 // FlagF32 return float32 flag
 func (c *Context) FlagF32(key string) float32 {
-	return strToF32(c.FlagStr(key))
+	return must(c.FlagF32E(key)).(float32)
 }
 
 // FlagF64 return float64 flag
 func (c *Context) FlagF64(key string) float64 {
-	return strToF64(c.FlagStr(key))
+	return must(c.FlagF64E(key)).(float64)
+}
+
+// FlagDuration return time.Duration flag
+func (c *Context) FlagDuration(key string) time.Duration {
+	return must(c.FlagDurationE(key)).(time.Duration)
 }
 
 // FlagList return list flag
@@ -160,134 +214,81 @@ func (c *Context) FlagList(key string) []string {
 // 	 }
 // 	 ctx.Bind(&flag)
 // }
+// panic with BindError if fail, see BindE
 func (c *Context) Bind(flags interface{}) {
-	value := reflect.ValueOf(flags)
-	if value.Kind() != reflect.Ptr {
-		panic(fmt.Errorf("bind must be pointer"))
+	if err := c.BindE(flags); err != nil {
+		panic(err)
 	}
+}
+
+//////////////////////////////////////////////
+// Flow control
+//////////////////////////////////////////////
 
-	if value.Elem().Kind() != reflect.Struct {
-		panic(fmt.Errorf("bind just support struct"))
+// Next executes the pending handlers in the chain inside the calling handler.
+// middlewares run from root to leaf first, then the commands.
+// stop on the first error returned by RunE
+func (c *Context) Next() {
+	if c.hands == nil {
+		c.hands = c.buildHandlers()
 	}
 
-	value = value.Elem()
-
-	for i := 0; i < value.NumField(); i++ {
-		field := value.Field(i)
-		vtype := value.Type().Field(i)
-		name := ""
-		tags := vtype.Tag.Get("cli")
-		if tags != "" {
-			name = tags
-		} else {
-			name = toKebabCase(vtype.Name)
-		}
+	c.index++
 
-		f := c.Flag(name)
-		if f == nil || f.Len() == 0 {
-			continue
-		}
+	for s := len(c.hands); c.index < s; c.index++ {
+		c.hands[c.index](c)
+	}
+}
 
-		kind := field.Kind()
-		if kind >= reflect.Bool && kind <= reflect.Float64 && kind != reflect.Uintptr {
-			err := c.bindValue(f.Get(), field, kind)
-			if err != nil {
-				panic(fmt.Errorf("bind field[%+v] fail,%+v", vtype.Name, err))
-			}
+// buildHandlers return middlewares of root and command chain, and then run of each command
+func (c *Context) buildHandlers() []Action {
+	hands := make([]Action, 0)
+	hands = append(hands, c.app.root.Middlewares...)
+	for _, cmd := range c.cmds {
+		hands = append(hands, cmd.Middlewares...)
+	}
 
-		} else if kind == reflect.Slice {
-			elem := vtype.Type.Elem()
-			slice := reflect.MakeSlice(elem, f.Len(), f.Len())
-			for i := 0; i < f.Len(); i++ {
-				val := slice.Index(i)
-				str := f.GetAt(i)
-				err := c.bindValue(str, val, elem.Kind())
-				if err != nil {
-					panic(fmt.Errorf("bind slice field[%+v] fail,%+v", vtype.Name, err))
-				}
+	for i, cmd := range c.cmds {
+		cmd := cmd
+		leaf := i == len(c.cmds)-1
+		hands = append(hands, func(ctx *Context) {
+			if cmd.IsDeprecated() {
+				ctx.app.printDeprecated(ctx, cmd)
 			}
-		} else if kind == reflect.Map {
-			// create map
-			field.Set(reflect.MakeMap(vtype.Type))
-
-			ft := vtype.Type
-			re := regexp.MustCompile("(=|:)")
-			for i := 0; i < f.Len(); i++ {
-				str := f.GetAt(i)
-				vk := re.Split(str, 2)
-				if len(vk) != 2 {
-					panic(fmt.Errorf("bind map field[%+v] fail, just support split by {=|:}", vtype.Name))
-				}
-
-				key := reflect.New(ft.Key()).Elem()
-				val := reflect.New(ft.Elem()).Elem()
-				var err error
-				err = c.bindValue(vk[0], key, ft.Key().Kind())
-				if err != nil {
-					panic(fmt.Errorf("bind map field[%+v] fail,%+v", vtype.Name, err))
-				}
-
-				err = c.bindValue(vk[1], val, ft.Elem().Kind())
-				if err != nil {
-					panic(fmt.Errorf("bind map field[%+v] fail, %+v", vtype.Name, err))
-				}
-
-				field.SetMapIndex(key, val)
+
+			if leaf {
+				ctx.runLeaf(cmd)
+			} else {
+				ctx.runCommand(cmd)
 			}
-		}
+		})
 	}
+
+	return hands
 }
 
-// bindValue just bind the value of the basic type
-func (c *Context) bindValue(str string, value reflect.Value, kind reflect.Kind) error {
-	switch kind {
-	case reflect.String:
-		value.SetString(str)
-	case reflect.Bool:
-		v, err := strconv.ParseBool(str)
-		if err != nil {
-			return err
-		}
-		value.SetBool(v)
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		v, err := strconv.ParseInt(str, 10, 64)
-		if err != nil {
-			return err
-		}
-		value.SetInt(v)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		v, err := strconv.ParseUint(str, 10, 64)
-		if err != nil {
-			return err
-		}
-		value.SetUint(v)
-	case reflect.Float32, reflect.Float64:
-		v, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return err
-		}
-		value.SetFloat(v)
-	default:
-		return fmt.Errorf("not support type:%+v", value.Kind())
+// runCommand call Run or RunE of command, abort if RunE return error
+func (c *Context) runCommand(cmd *Command) {
+	if cmd.Run != nil {
+		cmd.Run(c)
 	}
 
-	return nil
+	if cmd.RunE != nil {
+		if err := cmd.RunE(c); err != nil {
+			c.AbortWithError(&CommandError{Path: c.commandPath(cmd), Err: err})
+		}
+	}
 }
 
-//////////////////////////////////////////////
-// Flow control
-//////////////////////////////////////////////
-
-// Next executes the pending handlers in the chain inside the calling handler.
-func (c *Context) Next() {
-	c.index++
-
-	for s := len(c.cmds); c.index < s; c.index++ {
-		cmd := c.cmds[c.index]
-		if cmd.Run != nil {
-			cmd.Run(c)
+// commandPath return path of command in chain, like 'kubectl create'
+func (c *Context) commandPath(cmd *Command) string {
+	for i, v := range c.cmds {
+		if v == cmd {
+			return c.app.commandPath(c.cmds[:i+1])
 		}
 	}
+
+	return c.app.commandPath([]*Command{cmd})
 }
 
 // Abort stop process
@@ -295,6 +296,20 @@ func (c *Context) Abort() {
 	c.index = abortIndex
 }
 
+// AbortWithError stop process and record error, only the first error is kept
+func (c *Context) AbortWithError(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+
+	c.Abort()
+}
+
+// Err return the first error of command chain
+func (c *Context) Err() error {
+	return c.err
+}
+
 // IsAborted returns true if the current context was aborted.
 func (c *Context) IsAborted() bool {
 	return c.index >= abortIndex
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UnknownCommandError command not found in command tree,
+// returned when the token after a command which has sub commands but no action matches no sub command,
+// token after a command with action is passed to the action as arg
+type UnknownCommandError struct {
+	Name        string   // unknown command name
+	Path        string   // parent command path, like 'kubectl create'
+	Suggestions []string // similar commands
+}
+
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf("unknown command '%s' for '%s'%s", e.Name, e.Path, didYouMean(e.Suggestions))
+}
+
+// UnknownFlagError flag not found in command chain
+type UnknownFlagError struct {
+	Name        string   // unknown flag, like '--dryrun'
+	Path        string   // command path, like 'kubectl create'
+	Suggestions []string // similar flags
+}
+
+func (e *UnknownFlagError) Error() string {
+	return fmt.Sprintf("unknown flag '%s' for '%s'%s", e.Name, e.Path, didYouMean(e.Suggestions))
+}
+
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(". Did you mean: %s?", strings.Join(suggestions, ", "))
+}
+
+// AmbiguousError abbreviation matches more than one command or flag
+type AmbiguousError struct {
+	Kind       string   // command or flag
+	Name       string   // abbreviation
+	Path       string   // command path
+	Candidates []string // all matched names
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("ambiguous %s '%s' for '%s', candidates: %s", e.Kind, e.Name, e.Path, strings.Join(e.Candidates, ", "))
+}
+
+// CommandError error returned by command action, with command path attached
+type CommandError struct {
+	Path string // command path, like 'kubectl create'
+	Err  error  // error returned by action
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s: %+v", e.Path, e.Err)
+}
+
+// Unwrap return the error returned by action
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExitError external executable such as plugin exit with non-zero code,
+// ExitCode return the same code, so App.Run exit with it
+type ExitError struct {
+	Path string // executable path
+	Code int    // exit code of executable
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("%s exit with code %d", e.Path, e.Code)
+}
+
+// ErrMissingValue argument is missing
+var ErrMissingValue = errors.New("missing value")
+
+// InvalidValueError value of flag or argument can not be converted
+type InvalidValueError struct {
+	Flag  string // flag name, empty if argument
+	Index int    // argument index, start from 0
+	Arg   string // argument name, optional
+	Value string // raw value
+	Type  string // expected type
+	Err   error  // convert error or ErrMissingValue
+}
+
+func (e *InvalidValueError) Error() string {
+	target := fmt.Sprintf("argument #%d", e.Index+1)
+	if e.Arg != "" {
+		target = fmt.Sprintf("argument #%d <%s>", e.Index+1, e.Arg)
+	}
+
+	if e.Flag != "" {
+		target = fmt.Sprintf("flag --%s", e.Flag)
+	}
+
+	if e.Err == ErrMissingValue {
+		return fmt.Sprintf("missing %s", target)
+	}
+
+	return fmt.Sprintf("invalid value '%s' for %s, expect %s", e.Value, target, e.Type)
+}
+
+// Unwrap return the convert error
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// BindError all failed fields of Context.Bind
+type BindError struct {
+	Errors []error
+}
+
+func (e *BindError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("bind fail: %s", strings.Join(msgs, "; "))
+}
+
+// isInvalid return true if flag, or argument at index if flag is empty, fail to convert
+func (e *BindError) isInvalid(flag string, index int) bool {
+	for _, err := range e.Errors {
+		if v, ok := err.(*InvalidValueError); ok && v.Flag == flag && (flag != "" || v.Index == index) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidationError bound field violate validation tag
+type ValidationError struct {
+	Target string // flag or argument, like 'flag --port'
+	Rule   string // validation tag, like 'min'
+	Msg    string // detail
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Target, e.Msg)
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 )
 
 // Flag option of console
@@ -13,6 +15,10 @@ type Flag struct {
 	Usage    string   // describe
 	Required bool     // required field
 	Multiple bool     // enable multiple options
+	Secret   bool     // mask value in crash reports
+	Hidden   bool     // not show in help
+	Env      string   // environment variable used if not in command line
+	Enum     []string // allowed values
 	used     bool     // appear in command line
 	options  []string // command line option
 }
@@ -35,8 +41,21 @@ func (f *Flag) addOption(opt string) error {
 	return nil
 }
 
+// reset clear command line state, so app can run more than once
+func (f *Flag) reset() {
+	f.used = false
+	f.options = nil
+}
+
 func (f *Flag) validate() error {
-	if f.Required && !f.used {
+	// set env to options
+	if !f.used && f.Env != "" {
+		if env, ok := os.LookupEnv(f.Env); ok {
+			f.options = append(f.options, env)
+		}
+	}
+
+	if f.Required && !f.used && len(f.options) == 0 {
 		return fmt.Errorf("option is required:%+v", f.Name)
 	}
 
@@ -45,9 +64,27 @@ func (f *Flag) validate() error {
 		f.options = append(f.options, f.Value)
 	}
 
+	if len(f.Enum) > 0 {
+		for _, opt := range f.options {
+			if !f.inEnum(opt) {
+				return &InvalidValueError{Flag: f.Name, Value: opt, Type: "one of " + strings.Join(f.Enum, "|")}
+			}
+		}
+	}
+
 	return nil
 }
 
+func (f *Flag) inEnum(value string) bool {
+	for _, v := range f.Enum {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Get return option or default value
 func (f *Flag) Get() string {
 	if len(f.options) > 0 {
@@ -71,6 +108,11 @@ func (f *Flag) GetList() []string {
 	return f.options
 }
 
+// Used return true if flag appear in command line
+func (f *Flag) Used() bool {
+	return f.used
+}
+
 // Len return the length of the option
 func (f *Flag) Len() int {
 	return len(f.options)
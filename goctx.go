@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// goctx lazily create context.Context of command, which is canceled on SIGINT/SIGTERM,
+// the second signal force exit with 128+signal
+type goctx struct {
+	parent context.Context    // parent context
+	start  time.Time          // start time of command, deadline is start+Timeout
+	ctx    context.Context    // cancellable context
+	stop   context.CancelFunc // cancel ctx and stop signal handling
+	once   sync.Once
+}
+
+// Ctx return cancellable context.Context, canceled on SIGINT/SIGTERM or Timeout of command,
+// Timeout is counted from the start of command, not the first call of Ctx,
+// signal is only handled after Ctx is called, so the default behavior is kept
+func (c *Context) Ctx() context.Context {
+	c.once.Do(func() {
+		parent := c.parent
+		if parent == nil {
+			parent = context.Background()
+		}
+
+		ctx, stop := context.WithCancel(parent)
+		if timeout := c.timeout(); timeout > 0 {
+			start := c.start
+			if start.IsZero() {
+				start = time.Now()
+			}
+
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithDeadline(ctx, start.Add(timeout))
+			cancelCtx := stop
+			stop = func() {
+				cancelTimeout()
+				cancelCtx()
+			}
+		}
+
+		signals := make(chan os.Signal, 2)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		done := make(chan struct{})
+		go watchSignals(signals, done, stop)
+
+		c.ctx = ctx
+		c.stop = func() {
+			signal.Stop(signals)
+			close(done)
+			stop()
+		}
+	})
+
+	return c.ctx
+}
+
+// timeout return the nearest Timeout from leaf to root
+func (c *Context) timeout() time.Duration {
+	chain := c.chain()
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].Timeout > 0 {
+			return chain[i].Timeout
+		}
+	}
+
+	return 0
+}
+
+// release cancel context and stop signal handling
+func (c *Context) release() {
+	if c.stop != nil {
+		c.stop()
+	}
+}
+
+// watchSignals cancel on first signal, exit on second signal
+func watchSignals(signals chan os.Signal, done chan struct{}, stop context.CancelFunc) {
+	count := 0
+	for {
+		select {
+		case sig := <-signals:
+			count++
+			if count > 1 {
+				code := 1
+				if s, ok := sig.(syscall.Signal); ok {
+					code = 128 + int(s)
+				}
+
+				os.Exit(code)
+			}
+
+			stop()
+		case <-done:
+			return
+		}
+	}
+}
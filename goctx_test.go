@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeoutFromStart(t *testing.T) {
+	var err error
+	app := New()
+	app.AddCommands([]*Command{{
+		Name:    "wait",
+		Timeout: 20 * time.Millisecond,
+		Run: func(ctx *Context) {
+			time.Sleep(30 * time.Millisecond)
+			err = ctx.Ctx().Err()
+		},
+	}})
+
+	if e := app.RunArgs([]string{"t", "wait"}); e != nil {
+		t.Fatal(e)
+	}
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("deadline should be counted from start of command, %+v", err)
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 )
 
@@ -23,6 +25,8 @@ type Help struct {
 	indent string
 	last   string
 	size   int
+	out    io.Writer // custom output
+	dst    io.Writer // current output
 }
 
 // SetIndent set indent
@@ -30,9 +34,19 @@ func (h *Help) SetIndent(indent string) {
 	h.indent = indent
 }
 
+// SetOutput set output stream, default is output stream of Context
+func (h *Help) SetOutput(w io.Writer) {
+	h.out = w
+}
+
 // Write print string without indent, and auto add \n and ignore ""
+// format is used as plain text if no args
 func (h *Help) Write(format string, args ...interface{}) {
-	content := fmt.Sprintf(format, args...)
+	content := format
+	if len(args) > 0 {
+		content = fmt.Sprintf(format, args...)
+	}
+
 	if content == "" {
 		return
 	}
@@ -41,7 +55,11 @@ func (h *Help) Write(format string, args ...interface{}) {
 		content += "\n"
 	}
 
-	fmt.Printf(content)
+	if h.dst == nil {
+		h.dst = os.Stdout
+	}
+
+	io.WriteString(h.dst, content)
 
 	h.size += len(content)
 	if len(content) > 2 {
@@ -51,7 +69,8 @@ func (h *Help) Write(format string, args ...interface{}) {
 	}
 }
 
-// WriteIndent print content with prefix, auto split multi lines and add space
+// WriteIndent print content with prefix, auto split multi lines and add space,
+// format is used as plain text if no args
 // -v, --verbose               Noisy logging, including all shell commands executed.
 //                             If used with --help, shows hidden options.
 func (h *Help) WriteIndent(prefix string, format string, args ...interface{}) {
@@ -60,7 +79,11 @@ func (h *Help) WriteIndent(prefix string, format string, args ...interface{}) {
 		return
 	}
 
-	content := fmt.Sprintf(format, args...)
+	content := format
+	if len(args) > 0 {
+		content = fmt.Sprintf(format, args...)
+	}
+
 	if content == "" {
 		h.Write(prefix)
 		return
@@ -103,6 +126,14 @@ func (h *Help) Build(ctx *Context) {
 		h.indent = "  "
 	}
 
+	// reset state of previous build, help may be built more than once
+	h.size = 0
+	h.last = ""
+	h.dst = h.out
+	if h.dst == nil {
+		h.dst = ctx.Out()
+	}
+
 	target := h.GetTargetCommand(ctx)
 
 	// write header
@@ -130,7 +161,7 @@ func (h *Help) WriteUsage(ctx *Context) {
 	cmds := ctx.CommandList()
 
 	if len(cmds) == 0 {
-		h.Write("Usage: %s [<args>] [<options>]", app.Name)
+		h.Write("Usage: %s %s [<options>]", app.Name, app.Root().ArgsUsage())
 	} else {
 		builder := strings.Builder{}
 
@@ -144,7 +175,7 @@ func (h *Help) WriteUsage(ctx *Context) {
 
 		cmdsName := builder.String()
 
-		h.Write("Usage: %s %s [<args>] [<options>]", app.Name, cmdsName)
+		h.Write("Usage: %s %s %s [<options>]", app.Name, cmdsName, cmds[len(cmds)-1].ArgsUsage())
 	}
 }
 
@@ -184,6 +215,10 @@ func (h *Help) WriteOptions(ctx *Context, cmd *Command, head string, hasIndent b
 
 	maxLen := 0
 	for _, f := range cmd.Flags {
+		if f.Hidden {
+			continue
+		}
+
 		fname := f.FullName()
 		if fname != "" && len(fname) > maxLen {
 			maxLen = len(fname)
@@ -200,6 +235,10 @@ func (h *Help) WriteOptions(ctx *Context, cmd *Command, head string, hasIndent b
 	}
 
 	for _, f := range cmd.Flags {
+		if f.Hidden {
+			continue
+		}
+
 		prefix := ""
 		if f.Short != "" {
 			prefix = fmt.Sprintf("%s-%s,--%-*s", indent, f.Short, maxLen, f.FullName())
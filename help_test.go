@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHelpPercentShort(t *testing.T) {
+	var out bytes.Buffer
+	app := New()
+	app.Out = &out
+	app.AddCommands([]*Command{{Name: "sync", Short: "50% done", Run: func(ctx *Context) {}}})
+	if err := app.RunArgs([]string{"t", "--help"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "50% done") || strings.Contains(out.String(), "%!") {
+		t.Errorf("short should be printed as plain text, %s", out.String())
+	}
+}
+
+func TestHelpRepeated(t *testing.T) {
+	var out bytes.Buffer
+	app := New()
+	app.Out = &out
+	app.AddCommands([]*Command{{Name: "sync", Short: "sync all", Run: func(ctx *Context) {}}})
+	// footer leaves no divider at the end of the first build
+	app.Root().Footer = "see docs"
+	if err := app.RunArgs([]string{"t", "--help"}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := out.String()
+	out.Reset()
+	if err := app.RunArgs([]string{"t", "--help"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != first {
+		t.Errorf("help should be the same when built again:\n%q\n%q", first, out.String())
+	}
+}
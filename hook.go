@@ -0,0 +1,118 @@
+package cli
+
+import "fmt"
+
+// Use add global middlewares, same as Root().Use
+func (app *App) Use(middlewares ...Action) {
+	app.root.Use(middlewares...)
+}
+
+// SetBefore set hook run before the leaf command, same as Root().PersistentPreRun
+func (app *App) SetBefore(hook ActionE) {
+	app.root.PersistentPreRun = hook
+}
+
+// SetAfter set hook run after the leaf command, same as Root().PersistentPostRun
+func (app *App) SetAfter(hook ActionE) {
+	app.root.PersistentPostRun = hook
+}
+
+// SetFinally set hook always run at last, same as Root().PersistentFinally
+func (app *App) SetFinally(hook Action) {
+	app.root.PersistentFinally = hook
+}
+
+// chain return root and command chain
+func (c *Context) chain() []*Command {
+	cmds := make([]*Command, 0, len(c.cmds)+1)
+	cmds = append(cmds, c.app.root)
+	return append(cmds, c.cmds...)
+}
+
+// runLeaf run persistent pre hooks from root to leaf and PreRun, then the action,
+// then PostRun and persistent post hooks from leaf to root, stop if aborted
+func (c *Context) runLeaf(leaf *Command) {
+	chain := c.chain()
+	for _, cmd := range chain {
+		if !c.runHook(leaf, cmd.PersistentPreRun) {
+			return
+		}
+	}
+
+	if !c.runHook(leaf, leaf.PreRun) {
+		return
+	}
+
+	c.runCommand(leaf)
+	if c.IsAborted() {
+		return
+	}
+
+	if !c.runHook(leaf, leaf.PostRun) {
+		return
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !c.runHook(leaf, chain[i].PersistentPostRun) {
+			return
+		}
+	}
+}
+
+// runHook return false if hook return error or abort
+func (c *Context) runHook(leaf *Command, hook ActionE) bool {
+	if hook == nil {
+		return true
+	}
+
+	if err := hook(c); err != nil {
+		c.AbortWithError(&CommandError{Path: c.commandPath(leaf), Err: err})
+	}
+
+	return !c.IsAborted()
+}
+
+// runFinally run Finally of leaf and persistent finally hooks from leaf to root,
+// all hooks are called even if one of them panic, recovered is the panic of command chain,
+// which is raised again and panics of hooks are reported to error stream,
+// otherwise the first panic of hooks is raised
+func (c *Context) runFinally(recovered interface{}) {
+	chain := c.chain()
+	hooks := make([]Action, 0, len(chain)+1)
+	if len(c.cmds) > 0 {
+		hooks = append(hooks, c.cmds[len(c.cmds)-1].Finally)
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		hooks = append(hooks, chain[i].PersistentFinally)
+	}
+
+	panics := make([]interface{}, 0)
+	for _, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					panics = append(panics, r)
+				}
+			}()
+
+			hook(c)
+		}()
+	}
+
+	if recovered == nil && len(panics) > 0 {
+		recovered, panics = panics[0], panics[1:]
+	}
+
+	for _, r := range panics {
+		fmt.Fprintf(c.ErrOut(), "panic in finally hook: %+v\n", r)
+	}
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFinallyKeepOriginalPanic(t *testing.T) {
+	var errOut bytes.Buffer
+	app := New()
+	app.Err = &errOut
+	app.AddCommands([]*Command{{
+		Name:    "boom",
+		Run:     func(ctx *Context) { panic("boom") },
+		Finally: func(ctx *Context) { panic("hook") },
+	}})
+
+	err := app.RunArgs([]string{"t", "boom"})
+	if fmt.Sprint(err) != "boom" {
+		t.Errorf("original panic should be kept, got %+v", err)
+	}
+
+	if !strings.Contains(errOut.String(), "hook") {
+		t.Errorf("panic of hook should be reported, %s", errOut.String())
+	}
+
+	app.AddCommands([]*Command{{
+		Name:    "ok",
+		Run:     func(ctx *Context) {},
+		Finally: func(ctx *Context) { panic("hook") },
+	}})
+
+	if err := app.RunArgs([]string{"t", "ok"}); fmt.Sprint(err) != "hook" {
+		t.Errorf("panic of hook should be raised without panic of command, got %+v", err)
+	}
+}
+
+// the app command run when no command is given, with its Finally
+func TestAppCommandFinally(t *testing.T) {
+	var ran, finally bool
+	app := New()
+	app.AddCommands([]*Command{{
+		Name:    AppCommandName,
+		Run:     func(ctx *Context) { ran = true },
+		Finally: func(ctx *Context) { finally = true },
+	}})
+
+	if err := app.RunArgs([]string{"t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ran || !finally {
+		t.Errorf("app command and its Finally should run, run %v, finally %v", ran, finally)
+	}
+}
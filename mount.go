@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mount graft root command of other app as sub command at path, like 'team deploy',
+// parent commands of path must exist, panic if path is invalid or already used.
+// flags, middlewares, hooks, header and footer of other root are kept by the mounted command,
+// default command of other become action of the mounted command,
+// global flags of other shadow the same flags of app inside the mounted command.
+// groups and languages of other are merged, conflicting ones are renamed to 'name.key'.
+// commands of other are moved, other should not be used after mounted
+func (app *App) Mount(path string, other *App) {
+	names := strings.Fields(path)
+	if len(names) == 0 {
+		panic(fmt.Errorf("mount path is empty"))
+	}
+
+	parent := app.root
+	for i, name := range names[:len(names)-1] {
+		if parent = parent.findSub(name); parent == nil {
+			panic(fmt.Errorf("mount %q: command %q not found", path, strings.Join(names[:i+1], " ")))
+		}
+	}
+
+	name := names[len(names)-1]
+	if name == "help" || parent.findSub(name) != nil {
+		panic(fmt.Errorf("mount %q: command %q already exists", path, name))
+	}
+
+	cmd := other.root
+	cmd.Name = name
+	mountDefault(cmd)
+
+	app.mountGroups(cmd, other.groups)
+	app.mountLanguages(cmd, other.languages)
+	parent.AddSub(cmd)
+}
+
+// mountDefault move default command into cmd and remove help
+func mountDefault(cmd *Command) {
+	subs := make([]*Command, 0, len(cmd.Subs))
+	for _, sub := range cmd.Subs {
+		if sub.Name == "help" {
+			continue
+		}
+
+		if sub.Name != AppCommandName {
+			subs = append(subs, sub)
+			continue
+		}
+
+		if !cmd.hasAction() {
+			cmd.RunE = mountAction(cmd, sub)
+			cmd.Args = sub.Args
+			cmd.PreRun = sub.PreRun
+			cmd.PostRun = sub.PostRun
+			cmd.Finally = sub.Finally
+		}
+
+		cmd.Flags = append(cmd.Flags, sub.Flags...)
+		cmd.Middlewares = append(cmd.Middlewares, sub.Middlewares...)
+	}
+
+	cmd.Subs = subs
+}
+
+// mountAction run action of default command only if cmd is the leaf,
+// because action of every command in chain is called
+func mountAction(cmd *Command, def *Command) ActionE {
+	return func(ctx *Context) error {
+		if cmds := ctx.CommandList(); cmds[len(cmds)-1] != cmd {
+			return nil
+		}
+
+		if def.Run != nil {
+			def.Run(ctx)
+		}
+
+		if def.RunE != nil {
+			return def.RunE(ctx)
+		}
+
+		return nil
+	}
+}
+
+// mountGroups merge groups, rename group of commands if desc conflict
+func (app *App) mountGroups(cmd *Command, groups map[string]string) {
+	for group, desc := range groups {
+		key := group
+		if old, ok := app.groups[group]; ok && old != desc {
+			key = cmd.Name + "." + group
+			walkSubs(cmd, func(sub *Command) {
+				if sub.Group == group {
+					sub.Group = key
+				}
+			})
+		}
+
+		app.AddGroup(key, desc)
+	}
+}
+
+// mountLanguages merge languages, rename key and reference of commands if value conflict
+func (app *App) mountLanguages(cmd *Command, languages map[string]string) {
+	if len(languages) == 0 {
+		return
+	}
+
+	// copy, the map may be shared by user
+	merged := make(map[string]string, len(app.languages)+len(languages))
+	for key, value := range app.languages {
+		merged[key] = value
+	}
+
+	for key, value := range languages {
+		old, ok := merged[key]
+		if !ok || old == value {
+			merged[key] = value
+			continue
+		}
+
+		renamed := cmd.Name + "." + key
+		merged[renamed] = value
+		rename := func(c *Command) {
+			if c.Short == "$"+key || (c.Short == "" && c.Name+"_s" == key) {
+				c.Short = "$" + renamed
+			}
+
+			if c.Deprecated == "$"+key {
+				c.Deprecated = "$" + renamed
+			}
+		}
+
+		rename(cmd)
+		walkSubs(cmd, rename)
+	}
+
+	app.languages = merged
+}
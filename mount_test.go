@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMountDefaultLeafOnly(t *testing.T) {
+	var ran []string
+	other := New()
+	other.AddCommands([]*Command{
+		{Name: AppCommandName, Run: func(ctx *Context) { ran = append(ran, "default") }},
+		{Name: "deploy", Run: func(ctx *Context) { ran = append(ran, "deploy") }},
+	})
+
+	app := New()
+	app.Mount("team", other)
+
+	if err := app.RunArgs([]string{"t", "team", "deploy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(ran, ",") != "deploy" {
+		t.Errorf("default action should not run for sub command, ran %v", ran)
+	}
+
+	ran = nil
+	if err := app.RunArgs([]string{"t", "team"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(ran, ",") != "default" {
+		t.Errorf("default action should run as leaf, ran %v", ran)
+	}
+}
+
+func TestMountFlagShadow(t *testing.T) {
+	var got []string
+	action := func(ctx *Context) { got = append(got, ctx.FlagStrOr("output", "")) }
+
+	other := New()
+	other.AddFlags([]*Flag{{Name: "output", Value: "json"}})
+	other.AddCommands([]*Command{{Name: "deploy", Run: action}})
+
+	app := New()
+	app.AddFlags([]*Flag{{Name: "output", Value: "yaml"}})
+	app.AddCommands([]*Command{{Name: "get", Run: action}})
+	app.Mount("team", other)
+
+	for _, args := range [][]string{{"t", "get"}, {"t", "team", "deploy"}, {"t", "team", "deploy", "--output=wide"}} {
+		if err := app.RunArgs(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if strings.Join(got, ",") != "yaml,json,wide" {
+		t.Errorf("flag of mounted app should shadow flag of app, got %v", got)
+	}
+}
+
+func TestMountRename(t *testing.T) {
+	other := New()
+	other.SetGroups(map[string]string{"basic": "Team Commands:", "extra": "Extra Commands:"})
+	other.SetLanguage(map[string]string{"deploy_s": "deploy to cluster", "same": "same value"})
+	other.AddCommands([]*Command{
+		{Name: "deploy", Group: "basic", Run: func(ctx *Context) {}},
+		{Name: "sync", Group: "extra", Short: "$same", Run: func(ctx *Context) {}},
+	})
+
+	var out bytes.Buffer
+	app := New()
+	app.Out = &out
+	app.SetGroups(map[string]string{"basic": "Basic Commands:"})
+	app.SetLanguage(map[string]string{"deploy_s": "deploy the app", "same": "same value"})
+	app.AddCommands([]*Command{{Name: "deploy", Group: "basic", Run: func(ctx *Context) {}}})
+	app.Mount("team", other)
+
+	team := app.Root().findSub("team")
+	deploy := team.findSub("deploy")
+	if deploy.Group != "team.basic" || app.GetGroup("team.basic") != "Team Commands:" {
+		t.Errorf("conflicting group should be renamed, %q", deploy.Group)
+	}
+
+	if team.findSub("sync").Group != "extra" || app.GetGroup("extra") != "Extra Commands:" {
+		t.Errorf("new group should be merged as is")
+	}
+
+	if deploy.Short != "$team.deploy_s" || app.Translate(deploy.Short, "") != "deploy to cluster" {
+		t.Errorf("conflicting language should be renamed, %q", deploy.Short)
+	}
+
+	if app.Translate("", "deploy_s") != "deploy the app" || app.Translate("$same", "") != "same value" {
+		t.Errorf("languages of app should be kept")
+	}
+
+	if err := app.RunArgs([]string{"t", "team", "--help"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "Team Commands:") || !strings.Contains(out.String(), "deploy to cluster") {
+		t.Errorf("help should use renamed group and language, %s", out.String())
+	}
+}
+
+func TestMountInvalid(t *testing.T) {
+	app := New()
+	app.AddCommands([]*Command{{Name: "get", Run: func(ctx *Context) {}}})
+
+	for _, path := range []string{"", "get", "help", "missing team"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("mount %q should panic", path)
+				}
+			}()
+
+			app.Mount(path, New())
+		}()
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnableMultiCall dispatch on program name like busybox, name is the real program name,
+// one binary linked as 'name-get' or 'get' run as 'name get',
+// 'name-create-deployment' run as 'name create deployment',
+// dash in command name is replaced by underscore like plugins, such as 'name-api_resources',
+// unknown program name run as name, see InstallLinks
+func (app *App) EnableMultiCall(name string) {
+	app.multiCall = name
+}
+
+// multiCallArgs return command path selected by program name
+func (app *App) multiCallArgs(program string) []string {
+	if app.multiCall == "" {
+		return nil
+	}
+
+	app.Name = app.multiCall
+	base := strings.TrimSuffix(filepath.Base(program), ".exe")
+	if base == app.multiCall {
+		return nil
+	}
+
+	names := strings.Split(strings.TrimPrefix(base, app.multiCall+"-"), "-")
+	cmd := app.root
+	for i, name := range names {
+		names[i] = strings.Replace(name, "_", "-", -1)
+		if cmd = cmd.findSub(names[i]); cmd == nil {
+			return nil
+		}
+	}
+
+	return names
+}
+
+// linkName return link name of command path, like 'kubectl-api_resources'
+func (app *App) linkName(names []string) string {
+	parts := []string{app.multiCall}
+	for _, name := range names {
+		parts = append(parts, strings.Replace(name, "-", "_", -1))
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// InstallLinks create symlinks to current executable in dir for command paths, like 'create deployment',
+// all visible top level commands are used if paths is empty, existing links to executable are skipped,
+// return created links
+func (app *App) InstallLinks(dir string, paths ...string) ([]string, error) {
+	if app.multiCall == "" {
+		return nil, fmt.Errorf("multi-call is not enabled")
+	}
+
+	target, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		for _, sub := range app.root.Subs {
+			if sub.Name != AppCommandName && sub.Name != "help" && !sub.Hidden {
+				paths = append(paths, sub.Name)
+			}
+		}
+	}
+
+	links := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names := strings.Fields(path)
+		cmd := app.root
+		for _, name := range names {
+			if cmd = cmd.findSub(name); cmd == nil {
+				return links, fmt.Errorf("command %q not found", path)
+			}
+		}
+
+		link := filepath.Join(dir, app.linkName(names))
+		if isLinkTo(link, target) {
+			continue
+		}
+
+		if err := os.Symlink(target, link); err != nil {
+			return links, err
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// UninstallLinks remove symlinks to current executable in dir, return removed links
+func (app *App) UninstallLinks(dir string) ([]string, error) {
+	if app.multiCall == "" {
+		return nil, fmt.Errorf("multi-call is not enabled")
+	}
+
+	target, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]string, 0)
+	for _, info := range infos {
+		link := filepath.Join(dir, info.Name())
+		if !strings.HasPrefix(info.Name(), app.multiCall+"-") || !isLinkTo(link, target) {
+			continue
+		}
+
+		if err := os.Remove(link); err != nil {
+			return links, err
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// isLinkTo return true if path is symlink to target
+func isLinkTo(path string, target string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+
+	linkInfo, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	targetInfo, err := os.Stat(target)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(linkInfo, targetInfo)
+}
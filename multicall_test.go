@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newMultiCallApp(ran *[]string) *App {
+	action := func(ctx *Context) {
+		names := make([]string, 0)
+		for _, c := range ctx.CommandList() {
+			names = append(names, c.Name)
+		}
+
+		*ran = append(*ran, strings.Join(append(names, ctx.Args()...), " "))
+	}
+
+	create := &Command{Name: "create"}
+	create.AddSub(&Command{Name: "deployment", Run: action})
+
+	app := New()
+	app.EnableMultiCall("kubectl")
+	app.AddCommands([]*Command{
+		create,
+		{Name: "get", Run: action},
+		{Name: "api-resources", Run: action},
+		{Name: "secret", Hidden: true, Run: action},
+	})
+
+	return app
+}
+
+func TestMultiCallDispatch(t *testing.T) {
+	var ran []string
+	app := newMultiCallApp(&ran)
+
+	for _, program := range []string{
+		"/usr/bin/kubectl-get",
+		"kubectl-create-deployment.exe",
+		"kubectl-api_resources",
+		"kubectl",
+	} {
+		if err := app.RunArgs([]string{program, "get", "pods"}); err != nil {
+			t.Fatalf("run %s fail, %+v", program, err)
+		}
+	}
+
+	expect := []string{
+		"get get pods",
+		"create deployment get pods",
+		"api-resources get pods",
+		"get pods",
+	}
+
+	if !reflect.DeepEqual(ran, expect) {
+		t.Errorf("dispatch fail, got %q", ran)
+	}
+
+	if app.Name != "kubectl" {
+		t.Errorf("name should be real program name, %q", app.Name)
+	}
+
+	if args := app.multiCallArgs("kubectl-unknown"); args != nil {
+		t.Errorf("unknown program name should run as name, %v", args)
+	}
+}
+
+func TestInstallLinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "multicall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var ran []string
+	app := newMultiCallApp(&ran)
+
+	links, err := app.InstallLinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	for _, link := range links {
+		names = append(names, filepath.Base(link))
+	}
+
+	if !reflect.DeepEqual(names, []string{"kubectl-create", "kubectl-get", "kubectl-api_resources"}) {
+		t.Errorf("visible top level commands should be linked, got %v", names)
+	}
+
+	links, err = app.InstallLinks(dir, "get", "create deployment")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(links) != 1 || filepath.Base(links[0]) != "kubectl-create-deployment" {
+		t.Errorf("existing links should be skipped, got %v", links)
+	}
+
+	if _, err := app.InstallLinks(dir, "missing"); err == nil {
+		t.Errorf("unknown command should fail")
+	}
+
+	// files not linked to executable are kept
+	other := filepath.Join(dir, "kubectl-other")
+	if err := ioutil.WriteFile(other, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := app.UninstallLinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 4 {
+		t.Errorf("all links should be removed, got %v", removed)
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(infos) != 1 || infos[0].Name() != "kubectl-other" {
+		t.Errorf("only links should be removed, left %v", infos)
+	}
+}
+
+func TestMultiCallDisabled(t *testing.T) {
+	app := New()
+	if _, err := app.InstallLinks(os.TempDir()); err == nil {
+		t.Errorf("install should fail if multi-call is not enabled")
+	}
+
+	if _, err := app.UninstallLinks(os.TempDir()); err == nil {
+		t.Errorf("uninstall should fail if multi-call is not enabled")
+	}
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EnablePlugins enable git style external executable, 'app foo bar' will run
+// 'app-foo-bar' or 'app-foo' found in dirs or PATH when no built-in command matched,
+// dash in command name is replaced by underscore, like 'app-foo_bar' for 'app foo-bar',
+// token like path is never used as plugin name, flags before the command are passed to plugin
+func (app *App) EnablePlugins(dirs ...string) {
+	app.plugins = true
+	app.pluginDirs = append(app.pluginDirs, dirs...)
+}
+
+// addPluginCommand add built-in command: plugin list
+func (app *App) addPluginCommand() {
+	if app.root.findSub("plugin") != nil {
+		return
+	}
+
+	app.root.AddSub(&Command{
+		Name:  "plugin",
+		Short: "Provides utilities for interacting with plugins.",
+		Subs: []*Command{
+			{
+				Name:  "list",
+				Short: "List all visible plugin executables",
+				Run:   app.listPlugins,
+			},
+		},
+	})
+}
+
+// pluginPaths return plugin dirs and then PATH
+func (app *App) pluginPaths() []string {
+	paths := make([]string, 0)
+	paths = append(paths, app.pluginDirs...)
+	paths = append(paths, filepath.SplitList(os.Getenv("PATH"))...)
+	return paths
+}
+
+// pluginPrefix return prefix of plugin name, like 'kubectl-'
+func (app *App) pluginPrefix() string {
+	return app.Name + "-"
+}
+
+// executableExts extensions of executable on windows
+var executableExts = []string{".exe", ".bat", ".cmd"}
+
+// lookPlugin find plugin executable in plugin dirs and then PATH
+func (app *App) lookPlugin(name string) string {
+	for _, dir := range app.pluginDirs {
+		path := filepath.Join(dir, name)
+		if isExecutable(path) {
+			return path
+		}
+
+		if runtime.GOOS != "windows" {
+			continue
+		}
+
+		for _, ext := range executableExts {
+			if isExecutable(path + ext) {
+				return path + ext
+			}
+		}
+	}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// runPlugin find the longest matched plugin and run it, return false if not found
+func (app *App) runPlugin(ctx *Context, name string, args []string) bool {
+	if !isPluginToken(name) {
+		return false
+	}
+
+	parts := []string{app.Name}
+	for _, cmd := range ctx.CommandList() {
+		parts = append(parts, cmd.Name)
+	}
+
+	parts = append(parts, name)
+	base := len(parts)
+	for _, arg := range args {
+		if !isPluginToken(arg) {
+			break
+		}
+
+		parts = append(parts, arg)
+	}
+
+	for i := len(parts); i >= base; i-- {
+		names := make([]string, 0, i)
+		for _, p := range parts[:i] {
+			names = append(names, strings.Replace(p, "-", "_", -1))
+		}
+
+		names[0] = app.Name
+		path := app.lookPlugin(strings.Join(names, "-"))
+		if path == "" {
+			continue
+		}
+
+		app.execPlugin(ctx, path, append(append([]string{}, ctx.opts...), args[i-base:]...))
+		return true
+	}
+
+	return false
+}
+
+// execPlugin run plugin with args and environment, abort with *ExitError if plugin exit with non-zero code
+func (app *App) execPlugin(ctx *Context, path string, args []string) {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = ctx.In()
+	cmd.Stdout = ctx.Out()
+	cmd.Stderr = ctx.ErrOut()
+	cmd.Env = os.Environ()
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			ctx.AbortWithError(&ExitError{Path: path, Code: exitErr.ExitCode()})
+			return
+		}
+
+		panic(fmt.Errorf("run plugin %s fail, %+v", path, err))
+	}
+}
+
+// listPlugins print all plugins, and warn shadowed plugins and plugins overlapped with built-in command
+func (app *App) listPlugins(ctx *Context) {
+	prefix := app.pluginPrefix()
+	found := make(map[string]string)
+	count := 0
+	warnings := 0
+	for _, dir := range app.pluginPaths() {
+		if dir == "" {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if file.IsDir() || !strings.HasPrefix(file.Name(), prefix) {
+				continue
+			}
+
+			path := filepath.Join(dir, file.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			if count == 0 {
+				fmt.Fprintln(ctx.Out(), "The following compatible plugins are available:")
+				fmt.Fprintln(ctx.Out())
+			}
+
+			count++
+			fmt.Fprintln(ctx.Out(), path)
+
+			name := file.Name()
+			if ext := filepath.Ext(name); containsStr(executableExts, strings.ToLower(ext)) {
+				name = strings.TrimSuffix(name, ext)
+			}
+
+			if first, ok := found[name]; ok {
+				warnings++
+				fmt.Fprintf(ctx.ErrOut(), "  - warning: %s is overshadowed by a similarly named plugin: %s\n", path, first)
+			} else {
+				found[name] = path
+			}
+
+			if cmd := app.root.findSub(strings.Split(name[len(prefix):], "-")[0]); cmd != nil {
+				warnings++
+				fmt.Fprintf(ctx.ErrOut(), "  - warning: %s overwrites existing command: %q\n", path, cmd.Name)
+			}
+		}
+	}
+
+	if count == 0 {
+		panic(fmt.Errorf("unable to find any %s plugins in your PATH", app.Name))
+	}
+
+	if warnings > 0 {
+		fmt.Fprintf(ctx.ErrOut(), "error: %d plugin warnings were found\n", warnings)
+	}
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return containsStr(executableExts, strings.ToLower(filepath.Ext(path)))
+	}
+
+	return info.Mode()&0111 != 0
+}
+
+// isPluginToken return true if token can be part of plugin name, option and path are not
+func isPluginToken(token string) bool {
+	if token == "" || token[0] == '-' || token[0] == '/' {
+		return false
+	}
+
+	return !strings.ContainsAny(token, `/\`) && !strings.Contains(token, "..")
+}
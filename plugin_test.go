@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newPluginApp return app with plugin scripts in temp dir, scripts print name and args
+func newPluginApp(t *testing.T, scripts map[string]string) (*App, *bytes.Buffer, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin script need sh")
+	}
+
+	dir, err := ioutil.TempDir("", "cli-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, script := range scripts {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	app := New()
+	app.Name = "t"
+	app.Out = out
+	app.AddFlags([]*Flag{{Name: "verbose", Short: "v"}})
+	app.AddCommands([]*Command{NewCmd("get", "", func(ctx *Context) {})})
+	app.EnablePlugins(dir)
+	return app, out, dir
+}
+
+func TestPluginExitCode(t *testing.T) {
+	app, _, dir := newPluginApp(t, map[string]string{"t-fail": "exit 3"})
+	defer os.RemoveAll(dir)
+
+	err := app.RunArgs([]string{"t", "fail"})
+	if _, ok := err.(*ExitError); !ok {
+		t.Fatalf("plugin fail should return *ExitError, %+v", err)
+	}
+
+	if code := ExitCode(err); code != 3 {
+		t.Errorf("exit code should be 3, got %d", code)
+	}
+}
+
+func TestPluginLookup(t *testing.T) {
+	app, out, dir := newPluginApp(t, map[string]string{
+		"t-foo":     `echo foo "$@"`,
+		"t-foo-bar": `echo foo-bar "$@"`,
+		"t-a_b":     `echo a_b "$@"`,
+	})
+	defer os.RemoveAll(dir)
+
+	cases := map[string]string{
+		"foo x":                 "foo x",
+		"foo bar x":             "foo-bar x",
+		"foo bar --all x":       "foo-bar --all x",
+		"-v foo bar x":          "foo-bar -v x",
+		"--verbose=1 foo --a b": "foo --verbose=1 --a b",
+		"a-b":                   "a_b",
+	}
+
+	for args, expect := range cases {
+		out.Reset()
+		if err := app.RunArgs(append([]string{"t"}, strings.Fields(args)...)); err != nil {
+			t.Errorf("run %s fail, %+v", args, err)
+			continue
+		}
+
+		if got := strings.TrimSpace(out.String()); got != expect {
+			t.Errorf("run %s, expect %q, got %q", args, expect, got)
+		}
+	}
+}
+
+func TestPluginRejectPath(t *testing.T) {
+	app, out, dir := newPluginApp(t, map[string]string{"x": "echo escaped"})
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "sub")
+	os.Mkdir(sub, 0755)
+	app.pluginDirs = []string{sub}
+	for _, token := range []string{"../x", "..", "a/../../x"} {
+		err := app.RunArgs([]string{"t", token})
+		if _, ok := err.(*UnknownCommandError); !ok || out.Len() > 0 {
+			t.Errorf("token %s should not run plugin, %+v, %s", token, err, out.String())
+		}
+	}
+}
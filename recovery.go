@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const (
+	// ExitCodeError exit code of normal error
+	ExitCodeError = 1
+	// ExitCodePanic exit code of crash, same as EX_SOFTWARE
+	ExitCodePanic = 70
+)
+
+const maskValue = "******"
+
+// PanicError panic recovered by Recovery middleware
+type PanicError struct {
+	Value  interface{} // panic value
+	Stack  []byte      // stack of panic goroutine
+	Report string      // crash report file, empty if write fail
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %+v", e.Value)
+}
+
+// ExitCode return exit code of error, 0 if err is nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if _, ok := err.(*PanicError); ok {
+		return ExitCodePanic
+	}
+
+	if e, ok := err.(*ExitError); ok && e.Code > 0 {
+		return e.Code
+	}
+
+	return ExitCodeError
+}
+
+// Recovery return a middleware recover panic of commands, print a friendly message,
+// and write crash report with stack, masked argv, version and environment to StateDir()/crash
+func Recovery() Action {
+	return func(ctx *Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			app := ctx.App()
+			perr := &PanicError{Value: r, Stack: debug.Stack()}
+			report, err := app.writeCrashReport(ctx, perr)
+			if err == nil {
+				perr.Report = report
+				fmt.Fprintf(ctx.ErrOut(), "Oops, %s crashed unexpectedly: %+v\nA crash report was written to %s, please attach it when reporting this issue.\n", app.Name, r, report)
+			} else {
+				fmt.Fprintf(ctx.ErrOut(), "Oops, %s crashed unexpectedly: %+v\n%s\n", app.Name, r, perr.Stack)
+			}
+
+			ctx.AbortWithError(perr)
+		}()
+
+		ctx.Next()
+	}
+}
+
+// writeCrashReport write crash report file and return the path
+func (app *App) writeCrashReport(ctx *Context, perr *PanicError) (string, error) {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("App: %s\n", app.Name))
+	builder.WriteString(fmt.Sprintf("Version: %s\n", app.Version))
+	builder.WriteString(fmt.Sprintf("Time: %s\n", time.Now().Format(time.RFC3339)))
+	builder.WriteString(fmt.Sprintf("Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH))
+	builder.WriteString(fmt.Sprintf("Args: %s\n", joinArgs(maskArgs(ctx.Argv(), ctx.all))))
+	builder.WriteString(fmt.Sprintf("Panic: %+v\n", perr.Value))
+	builder.WriteString("\nStack:\n")
+	builder.Write(perr.Stack)
+	builder.WriteString("\nEnvironment:\n")
+	for _, env := range maskEnv(os.Environ(), ctx.all) {
+		builder.WriteString(env)
+		builder.WriteString("\n")
+	}
+
+	dir := filepath.Join(app.StateDir(), "crash")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("crash-%s-%d.log", time.Now().Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(builder.String()), 0600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// maskArgs replace value of secret flags with mask, flags contain keys of both name and short,
+// short flag with attached value like '-phunter2' and combined short flags like '-vp hunter2' are masked too
+func maskArgs(args []string, flags map[string]*Flag) []string {
+	result := make([]string, len(args))
+	copy(result, args)
+	for i := 0; i < len(result); i++ {
+		arg := result[i]
+		if arg == "" || (arg[0] != '-' && arg[0] != '/') {
+			continue
+		}
+
+		key := strings.TrimLeft(arg, "-/")
+		prefix := len(arg) - len(key)
+		index := strings.IndexAny(key, "=/:")
+		if index != -1 {
+			key = key[:index]
+		}
+
+		flag := flags[key]
+		if flag == nil && prefix == 1 && arg[0] == '-' && index == -1 && len(key) > 1 {
+			// -phunter2 or -vp hunter2
+			if first := flags[key[:1]]; first != nil && first.Secret {
+				result[i] = arg[:prefix+1] + maskValue
+				continue
+			}
+
+			if isShortFlags(key, flags) {
+				flag = flags[key[len(key)-1:]]
+			}
+		}
+
+		if flag == nil || !flag.Secret {
+			continue
+		}
+
+		if index != -1 {
+			result[i] = arg[:prefix+index+1] + maskValue
+		} else if i+1 < len(result) && result[i+1] != "" && result[i+1][0] != '-' && result[i+1][0] != '/' {
+			i++
+			result[i] = maskValue
+		}
+	}
+
+	return result
+}
+
+// isShortFlags return true if every char of key is a short flag
+func isShortFlags(key string, flags map[string]*Flag) bool {
+	for i := 0; i < len(key); i++ {
+		if flags[key[i:i+1]] == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// maskEnv replace value of environment which look like secret, or is Env of secret flag
+func maskEnv(envs []string, flags map[string]*Flag) []string {
+	keywords := []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "AUTH"}
+	secrets := make(map[string]bool)
+	for _, f := range flags {
+		if f.Secret && f.Env != "" {
+			secrets[f.Env] = true
+		}
+	}
+
+	result := make([]string, 0, len(envs))
+	for _, env := range envs {
+		index := strings.IndexByte(env, '=')
+		if index != -1 && secrets[env[:index]] {
+			env = env[:index+1] + maskValue
+		} else if index != -1 {
+			name := strings.ToUpper(env[:index])
+			for _, k := range keywords {
+				if strings.Contains(name, k) {
+					env = env[:index+1] + maskValue
+					break
+				}
+			}
+		}
+
+		result = append(result, env)
+	}
+
+	return result
+}
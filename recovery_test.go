@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMaskArgs(t *testing.T) {
+	verbose := &Flag{Name: "verbose", Short: "v"}
+	password := &Flag{Name: "password", Short: "p", Secret: true}
+	flags := map[string]*Flag{"verbose": verbose, "v": verbose, "password": password, "p": password}
+
+	cases := map[string]string{
+		"--password hunter2": "--password ******",
+		"--password=hunter2": "--password=******",
+		"-p hunter2":         "-p ******",
+		"-p=hunter2":         "-p=******",
+		"-phunter2":          "-p******",
+		"-vp hunter2":        "-vp ******",
+		"-v get":             "-v get",
+	}
+
+	for args, expect := range cases {
+		masked := strings.Join(maskArgs(strings.Fields(args), flags), " ")
+		if masked != expect {
+			t.Errorf("mask %q, expect %q, got %q", args, expect, masked)
+		}
+	}
+}
+
+func TestMaskEnv(t *testing.T) {
+	flags := map[string]*Flag{"db-pass": {Name: "db-pass", Secret: true, Env: "DB_PASS"}, "host": {Name: "host", Env: "DB_HOST"}}
+	envs := maskEnv([]string{"DB_PASS=hunter2", "DB_HOST=localhost", "API_TOKEN=abc"}, flags)
+	expect := []string{"DB_PASS=" + maskValue, "DB_HOST=localhost", "API_TOKEN=" + maskValue}
+	if !reflect.DeepEqual(envs, expect) {
+		t.Errorf("expect %q, got %q", expect, envs)
+	}
+}
+
+func TestRecoveryMaskShortSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-crash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	app := New()
+	app.Out = ioutil.Discard
+	app.Err = ioutil.Discard
+	app.SetStateDir(dir)
+	app.Use(Recovery())
+	cmd := NewCmd("boom", "", func(ctx *Context) { panic("boom") })
+	cmd.Flags = []*Flag{{Name: "password", Short: "p", Secret: true}, {Name: "db-pass", Secret: true, Env: "CLI_TEST_DB_PASS"}}
+	os.Setenv("CLI_TEST_DB_PASS", "envsecret")
+	defer os.Unsetenv("CLI_TEST_DB_PASS")
+	app.AddCommands([]*Command{cmd})
+
+	err = app.RunArgs([]string{"t", "boom", "-p", "hunter2"})
+	perr, ok := err.(*PanicError)
+	if !ok || perr.Report == "" {
+		t.Fatalf("expect PanicError with report, got %+v", err)
+	}
+
+	data, err := ioutil.ReadFile(perr.Report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "envsecret") {
+		t.Errorf("secret is not masked in crash report")
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultResponseDepth default max nested depth of response files
+const DefaultResponseDepth = 10
+
+// maxResponseLine max size of a line in response file
+const maxResponseLine = 16 * 1024 * 1024
+
+// EnableResponseFiles enable '@path' args expanded into args read from file,
+// each line is one arg, line with quotes is split like shell, such as '--name "a b" -o json',
+// so paths with spaces and backslashes need no escape, empty line and line start with '#' are ignored,
+// nested '@path' in file is relative to the file, '@@xx' is escaped to '@xx',
+// depth limit nested response files, 0 use DefaultResponseDepth
+func (app *App) EnableResponseFiles(depth int) {
+	if depth <= 0 {
+		depth = DefaultResponseDepth
+	}
+
+	app.responseDepth = depth
+}
+
+// expandResponseFiles expand all '@path' args recursively
+func (app *App) expandResponseFiles(args []string, dir string, depth int) []string {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") || len(arg) == 1 {
+			result = append(result, arg)
+			continue
+		}
+
+		if arg[1] == '@' {
+			result = append(result, arg[1:])
+			continue
+		}
+
+		if depth >= app.responseDepth {
+			panic(fmt.Errorf("response file %s exceeds max depth %d", arg, app.responseDepth))
+		}
+
+		path := arg[1:]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		lines, err := readResponseFile(path)
+		if err != nil {
+			panic(err)
+		}
+
+		result = append(result, app.expandResponseFiles(lines, filepath.Dir(path), depth+1)...)
+	}
+
+	return result
+}
+
+// readResponseFile read args from file
+func readResponseFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read response file fail, %+v", err)
+	}
+	defer file.Close()
+
+	args := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxResponseLine)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if !strings.ContainsAny(line, `"'`) {
+			args = append(args, line)
+			continue
+		}
+
+		parts, err := splitArgs(line)
+		if err != nil {
+			return nil, fmt.Errorf("bad response file %s:%d, %+v", path, lineNo, err)
+		}
+
+		args = append(args, parts...)
+	}
+
+	return args, scanner.Err()
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResponseFileLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-response")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := "# comment\n/tmp/my dir/file.txt\nC:\\dir\\file\n\n--name \"a b\" 'c\\d'\n"
+	path := filepath.Join(dir, "args")
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := readResponseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{"/tmp/my dir/file.txt", `C:\dir\file`, "--name", "a b", `c\d`}
+	if !reflect.DeepEqual(args, expect) {
+		t.Errorf("expect %q, got %q", expect, args)
+	}
+}
+
+func TestLongResponseLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-response")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	long := strings.Repeat("x", 100*1024)
+	path := filepath.Join(dir, "args")
+	if err := ioutil.WriteFile(path, []byte(long+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var args []string
+	app := New()
+	app.EnableResponseFiles(0)
+	app.AddCommands([]*Command{NewCmd("get", "", func(ctx *Context) { args = ctx.Args() })})
+	if err := app.RunArgs([]string{"t", "get", "@" + path}); err != nil {
+		t.Fatalf("long line should not fail, %+v", err)
+	}
+
+	if len(args) != 1 || args[0] != long {
+		t.Errorf("long line should be read as one arg, got %d args", len(args))
+	}
+}
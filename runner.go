@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"reflect"
+)
+
+// Runner command declared as type, keep state, flags and logic of command in one place,
+// Flags return nil to generate flags from tags of struct pointer, see ParseFlags.
+// Run is called only if the command is the leaf of command chain, before Run,
+// bound fields of struct pointer are reset, so values of previous run are not kept,
+// then flags and args are bound to struct pointer, see Context.Bind,
+// then Complete and Validate are called if implemented
+// example:
+//
+//	type GetCmd struct {
+//		Output   string `short:"o" enum:"json,yaml"`
+//		Resource string `arg:"0" required:"true"`
+//	}
+//
+//	func (c *GetCmd) Name() string              { return "get" }
+//	func (c *GetCmd) Flags() []*cli.Flag        { return nil }
+//	func (c *GetCmd) Run(ctx *cli.Context) error { ... }
+type Runner interface {
+	Name() string
+	Flags() []*Flag
+	Run(ctx *Context) error
+}
+
+// Completer fill fields which are not from command line, such as default and client
+type Completer interface {
+	Complete(ctx *Context) error
+}
+
+// Validator check fields before run, error is returned without running
+type Validator interface {
+	Validate() error
+}
+
+// SubRunner return sub commands of runner
+type SubRunner interface {
+	Subcommands() []Runner
+}
+
+// Describer fill other fields of the adapted command, such as Group, Short, Alias and Hidden
+type Describer interface {
+	Describe(cmd *Command)
+}
+
+// NewRunnerCmd adapt runner and its sub commands into command tree, panic if flag tags are invalid
+func NewRunnerCmd(r Runner) *Command {
+	cmd := &Command{Name: r.Name()}
+	cmd.Flags = r.Flags()
+	if cmd.Flags == nil && isStructPtr(r) {
+		flags, err := ParseFlags(r)
+		if err != nil {
+			panic(err)
+		}
+
+		cmd.Flags = flags
+	}
+
+	cmd.RunE = runnerAction(cmd, r)
+
+	if d, ok := r.(Describer); ok {
+		d.Describe(cmd)
+	}
+
+	if s, ok := r.(SubRunner); ok {
+		for _, sub := range s.Subcommands() {
+			cmd.AddSub(NewRunnerCmd(sub))
+		}
+	}
+
+	return cmd
+}
+
+// AddRunners add runner commands to root
+func (app *App) AddRunners(runners ...Runner) {
+	for _, r := range runners {
+		app.root.AddSub(NewRunnerCmd(r))
+	}
+}
+
+// AddRunners add runner commands as sub commands
+func (cmd *Command) AddRunners(runners ...Runner) {
+	for _, r := range runners {
+		cmd.AddSub(NewRunnerCmd(r))
+	}
+}
+
+// runnerAction run runner only if cmd is the leaf, because action of every command in chain is called
+func runnerAction(cmd *Command, r Runner) ActionE {
+	return func(ctx *Context) error {
+		if cmds := ctx.CommandList(); cmds[len(cmds)-1] != cmd {
+			return nil
+		}
+
+		return runRunner(ctx, r)
+	}
+}
+
+// runRunner reset, bind, complete, validate and run
+func runRunner(ctx *Context, r Runner) error {
+	if isStructPtr(r) {
+		resetStruct(reflect.ValueOf(r).Elem())
+	}
+
+	if _, ok := r.(Binder); ok || isStructPtr(r) {
+		if err := ctx.BindE(r); err != nil {
+			return err
+		}
+	}
+
+	if c, ok := r.(Completer); ok {
+		if err := c.Complete(ctx); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := r.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return r.Run(ctx)
+}
+
+func isStructPtr(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
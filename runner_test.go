@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type parentRunner struct {
+	Token string `arg:"0" required:"true"`
+	child *childRunner
+	ran   bool
+}
+
+func (r *parentRunner) Name() string          { return "parent" }
+func (r *parentRunner) Flags() []*Flag        { return nil }
+func (r *parentRunner) Subcommands() []Runner { return []Runner{r.child} }
+func (r *parentRunner) Run(ctx *Context) error {
+	r.ran = true
+	return nil
+}
+
+type childRunner struct {
+	Resource string `arg:"0"`
+	ran      bool
+}
+
+func (r *childRunner) Name() string   { return "child" }
+func (r *childRunner) Flags() []*Flag { return nil }
+func (r *childRunner) Run(ctx *Context) error {
+	r.ran = true
+	return nil
+}
+
+func TestRunnerLeafOnly(t *testing.T) {
+	parent := &parentRunner{child: &childRunner{}}
+	app := New()
+	app.AddRunners(parent)
+
+	if err := app.RunArgs([]string{"r", "parent", "child", "pods"}); err != nil {
+		t.Fatalf("run child fail, %+v", err)
+	}
+
+	if parent.ran || !parent.child.ran || parent.child.Resource != "pods" {
+		t.Errorf("only child should run, parent %v, child %+v", parent.ran, parent.child)
+	}
+
+	if err := app.RunArgs([]string{"r", "parent", "tok"}); err != nil {
+		t.Fatalf("run parent fail, %+v", err)
+	}
+
+	if !parent.ran || parent.Token != "tok" {
+		t.Errorf("parent should run as leaf, %+v", parent)
+	}
+}
+
+type clientRunner struct {
+	Output  string   `short:"o"`
+	Labels  []string `short:"l"`
+	Target  string   `arg:"0"`
+	Client  *http.Client
+	Writer  io.Writer
+	Hook    func()
+	Done    chan struct{}
+	Options struct {
+		Verbose bool
+	}
+	got clientSeen
+}
+
+// clientSeen values seen by Run
+type clientSeen struct {
+	Output string
+	Labels []string
+	Target string
+}
+
+func (r *clientRunner) Name() string   { return "client" }
+func (r *clientRunner) Flags() []*Flag { return nil }
+func (r *clientRunner) Run(ctx *Context) error {
+	r.got = clientSeen{Output: r.Output, Labels: r.Labels, Target: r.Target}
+	return nil
+}
+
+func TestRunnerFlagsSkipUnbound(t *testing.T) {
+	cmd := NewRunnerCmd(&clientRunner{})
+	names := make([]string, 0)
+	for _, f := range cmd.Flags {
+		names = append(names, f.Name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"output", "labels"}) {
+		t.Errorf("only bindable fields should be flags, got %v", names)
+	}
+}
+
+func TestRunnerReset(t *testing.T) {
+	client := &http.Client{}
+	r := &clientRunner{Client: client}
+	app := New()
+	app.AddRunners(r)
+
+	if err := app.RunArgs([]string{"r", "client", "a", "-o", "json", "-l", "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(r.got, clientSeen{Output: "json", Labels: []string{"x"}, Target: "a"}) {
+		t.Errorf("bind fail, %+v", r.got)
+	}
+
+	if err := app.RunArgs([]string{"r", "client"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(r.got, clientSeen{}) {
+		t.Errorf("values of previous run should be reset, %+v", r.got)
+	}
+
+	if r.Client != client {
+		t.Errorf("unbound field should be kept")
+	}
+}
@@ -0,0 +1,543 @@
+// Package scaffold generate new command following the layout of examples/kubectl/cmds:
+//
+//	NNname.go    handler, flag struct with cli tags, long describe and constructor
+//	language.go  name_s and name_l keys added to Langs
+//	commands.go  constructor registered in GetCommands
+//
+// nested command like 'rollout status' is registered by AddSub in constructor of parent,
+// parent registered inline in GetCommands like cli.NewCmd("rollout", ...) is moved into a new constructor,
+// file is named after parent like NNrollout-status.go
+//
+// NN is the file order, tens digit is the group and ones digit is the order in group
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flag flag of new command
+type Flag struct {
+	Name    string // like 'dry-run'
+	Short   string // like 'd'
+	Type    string // go type, see Types
+	Default string // default value
+	Usage   string // describe
+}
+
+// Config of new command
+type Config struct {
+	Dir   string // package dir of commands
+	Name  string // command name, like 'api-resources', or path of nested command, like 'rollout status'
+	Group string // group name, default 'Other'
+	Short string // short describe, saved in Langs
+	Long  string // long describe, saved in Langs
+	Flags []*Flag
+}
+
+// Types supported flag types
+var Types = []string{"bool", "string", "int", "uint", "float64", "time.Duration", "[]string", "[]int", "map[string]string"}
+
+var fileReg = regexp.MustCompile(`^(\d+)(.+)\.go$`)
+var nameReg = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// ParseFlag parse flag of format 'name[,short]:type[=default]', type is string if omitted
+func ParseFlag(str string) (*Flag, error) {
+	flag := &Flag{Type: "string"}
+	if i := strings.Index(str, "="); i != -1 {
+		flag.Default = str[i+1:]
+		str = str[:i]
+	}
+
+	if i := strings.Index(str, ":"); i != -1 {
+		flag.Type = str[i+1:]
+		str = str[:i]
+	}
+
+	if i := strings.Index(str, ","); i != -1 {
+		flag.Short = str[i+1:]
+		str = str[:i]
+	}
+
+	flag.Name = str
+	if err := flag.check(); err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+func (f *Flag) check() error {
+	if !nameReg.MatchString(f.Name) {
+		return fmt.Errorf("bad flag name %q, must be like 'dry-run'", f.Name)
+	}
+
+	if len(f.Short) > 1 {
+		return fmt.Errorf("short of flag --%s must be one char", f.Name)
+	}
+
+	for _, t := range Types {
+		if t == f.Type {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("type %s of flag --%s is not supported, must be one of %s", f.Type, f.Name, strings.Join(Types, ","))
+}
+
+// Generate write new command file and register it, return path of new file
+func Generate(conf Config) (string, error) {
+	if conf.Dir == "" {
+		conf.Dir = "."
+	}
+
+	if conf.Group == "" {
+		conf.Group = "Other"
+	}
+
+	names := strings.Fields(conf.Name)
+	if len(names) == 0 {
+		return "", fmt.Errorf("command name is empty")
+	}
+
+	for _, name := range names {
+		if !nameReg.MatchString(name) {
+			return "", fmt.Errorf("bad command name %q, must be like 'api-resources'", name)
+		}
+	}
+
+	for _, f := range conf.Flags {
+		if err := f.check(); err != nil {
+			return "", err
+		}
+	}
+
+	pkg, err := parsePackage(conf.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	// id is the command path joined by dash, like 'rollout-status', used by identifiers, lang keys and file name
+	id := strings.Join(names, "-")
+	if _, ok := pkg.ctors["newCmd"+upperCamel(id)]; ok {
+		return "", fmt.Errorf("command %s already exists", conf.Name)
+	}
+
+	if _, ok := pkg.groups[id]; ok && len(names) == 1 {
+		return "", fmt.Errorf("command %s already exists", conf.Name)
+	}
+
+	if pkg.langs == nil || (pkg.commands == nil && len(names) == 1) {
+		return "", fmt.Errorf("GetCommands or Langs not found in %s", conf.Dir)
+	}
+
+	camel := lowerCamel(id)
+	langs := fmt.Sprintf("%q: %q,\n%q: %sLongDesc,\n", id+"_s", conf.Short, id+"_l", camel)
+	edits := make(map[string][]edit)
+	edits[pkg.langs.file] = append(edits[pkg.langs.file], edit{offset: pkg.langs.rbrace, code: langs, list: true})
+
+	var file string
+	if len(names) == 1 {
+		file = pkg.fileName(id, conf.Group)
+		register := fmt.Sprintf("newCmd%s(),\n", upperCamel(id))
+		edits[pkg.commands.file] = append(edits[pkg.commands.file], edit{offset: pkg.commands.rbrace, code: register, list: true})
+	} else {
+		parentID := strings.Join(names[:len(names)-1], "-")
+		parent := "newCmd" + upperCamel(parentID)
+		register := fmt.Sprintf("newCmd%s()", upperCamel(id))
+		var parentFile string
+		if c, ok := pkg.ctors[parent]; ok {
+			parentFile = c.file
+			edits[c.file] = append(edits[c.file], edit{offset: c.ret, code: fmt.Sprintf("%s.AddSub(%s)\n", c.name, register)})
+		} else if in, ok := pkg.inlines[parentID]; ok && len(names) == 2 {
+			data, err := ioutil.ReadFile(in.file)
+			if err != nil {
+				return "", err
+			}
+
+			// move inline registration into constructor, which is added to file of handler
+			parentFile = in.file
+			if f, ok := pkg.funcs[in.handler]; ok {
+				parentFile = f
+			}
+
+			ctor := fmt.Sprintf("\nfunc %s() *cli.Command {\n\tcmd := %s\n\tcmd.AddSub(%s)\n\treturn cmd\n}\n", parent, data[in.start:in.end], register)
+			edits[in.file] = append(edits[in.file], edit{offset: in.start, end: in.end, code: parent + "()"})
+			edits[parentFile] = append(edits[parentFile], edit{offset: endOfFile, code: ctor})
+		} else {
+			return "", fmt.Errorf("parent command %s not found in %s, generate parent first", strings.Join(names[:len(names)-1], " "), conf.Dir)
+		}
+
+		file = id + ".go"
+		if m := fileReg.FindStringSubmatch(filepath.Base(parentFile)); m != nil {
+			file = m[1] + file
+		}
+	}
+
+	path := filepath.Join(conf.Dir, file)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+
+	src, err := format.Source(genCommand(pkg.name, names[len(names)-1], id, conf))
+	if err != nil {
+		return "", err
+	}
+
+	// write files after all sources are ready
+	sources := map[string][]byte{path: src}
+	for file, list := range edits {
+		if sources[file], err = applyEdits(file, list); err != nil {
+			return "", err
+		}
+	}
+
+	for file, data := range sources {
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	return path, nil
+}
+
+// genCommand return source of new command file, Short and Long of nested command refer to lang keys of id,
+// because lang keys of command name may conflict with other commands
+func genCommand(pkg string, name string, id string, conf Config) []byte {
+	camel := lowerCamel(id)
+	upper := upperCamel(id)
+	long := conf.Long
+	if long == "" {
+		long = conf.Short
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "package %s\n\n", pkg)
+	if hasDuration(conf.Flags) {
+		fmt.Fprintf(b, "import (\n\t\"time\"\n\n\t\"github.com/jeckbjy/cli\"\n)\n\n")
+	} else {
+		fmt.Fprintf(b, "import \"github.com/jeckbjy/cli\"\n\n")
+	}
+
+	fmt.Fprintf(b, "var %sLongDesc = `\n%s\n`\n\n", camel, strings.Replace(long, "`", "'", -1))
+	fmt.Fprintf(b, "type %sFlags struct {\n", camel)
+	for _, f := range conf.Flags {
+		tag := fmt.Sprintf("cli:%q", f.Name)
+		if f.Short != "" {
+			tag += fmt.Sprintf(" short:%q", f.Short)
+		}
+
+		if f.Default != "" {
+			tag += fmt.Sprintf(" default:%q", f.Default)
+		}
+
+		if f.Usage != "" {
+			tag += fmt.Sprintf(" usage:%q", f.Usage)
+		}
+
+		fmt.Fprintf(b, "\t%s %s `%s`\n", upperCamel(f.Name), f.Type, tag)
+	}
+
+	fmt.Fprintf(b, "}\n\n")
+	fmt.Fprintf(b, "func newCmd%s() *cli.Command {\n", upper)
+	fmt.Fprintf(b, "\tcmd := cli.NewCmd(%q, %q, onCmd%s)\n", name, conf.Group, upper)
+	if name != id {
+		fmt.Fprintf(b, "\tcmd.Short = %q\n\tcmd.Long = %q\n", "$"+id+"_s", "$"+id+"_l")
+	}
+
+	fmt.Fprintf(b, "\tcmd.AddStructFlags(&%sFlags{})\n\treturn cmd\n}\n\n", camel)
+	fmt.Fprintf(b, "func onCmd%s(ctx *cli.Context) {\n", upper)
+	fmt.Fprintf(b, "\tvar flags %sFlags\n\tctx.Bind(&flags)\n\n\t// TODO: process %s\n}\n", camel, conf.Name)
+	return b.Bytes()
+}
+
+func hasDuration(flags []*Flag) bool {
+	for _, f := range flags {
+		if f.Type == "time.Duration" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// literal composite literal to insert into
+type literal struct {
+	file   string
+	rbrace int // offset of closing brace
+}
+
+// ctor command constructor, like newCmdRollout
+type ctor struct {
+	file string
+	name string // name of returned command variable
+	ret  int    // offset of the last return statement
+}
+
+// inline command registered in GetCommands, like cli.NewCmd("rollout", "Deploy", onCmdRollout)
+type inline struct {
+	file    string
+	start   int    // offset of call
+	end     int    // end offset of call
+	handler string // name of handler func
+}
+
+// cmdPackage existing commands package
+type cmdPackage struct {
+	name     string
+	groups   map[string]string  // command name to group
+	orders   map[string]int     // command name to file order
+	ctors    map[string]*ctor   // constructors by func name
+	inlines  map[string]*inline // inline commands of GetCommands by name
+	funcs    map[string]string  // file of funcs by name
+	commands *literal           // returned literal of GetCommands
+	langs    *literal           // literal of Langs
+}
+
+func parsePackage(dir string) (*cmdPackage, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &cmdPackage{
+		groups:  make(map[string]string),
+		orders:  make(map[string]int),
+		ctors:   make(map[string]*ctor),
+		inlines: make(map[string]*inline),
+		funcs:   make(map[string]string),
+	}
+	for name, pkg := range pkgs {
+		p.name = name
+		for file, f := range pkg.Files {
+			if m := fileReg.FindStringSubmatch(filepath.Base(file)); m != nil {
+				order, _ := strconv.Atoi(m[1])
+				p.orders[m[2]] = order
+			}
+
+			p.inspect(fset, file, f)
+		}
+	}
+
+	if p.name == "" {
+		return nil, fmt.Errorf("no go package in %s", dir)
+	}
+
+	return p, nil
+}
+
+// inspect find command groups, GetCommands and Langs
+func (p *cmdPackage) inspect(fset *token.FileSet, file string, f *ast.File) {
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "NewCmd" && sel.Sel.Name != "NewCmdE") || len(n.Args) < 2 {
+				return true
+			}
+
+			name, ok1 := stringLit(n.Args[0])
+			group, ok2 := stringLit(n.Args[1])
+			if ok1 && ok2 {
+				p.groups[name] = group
+			}
+		case *ast.FuncDecl:
+			if n.Recv != nil || n.Body == nil {
+				return true
+			}
+
+			p.funcs[n.Name.Name] = file
+
+			if strings.HasPrefix(n.Name.Name, "newCmd") && len(n.Body.List) > 0 {
+				last := n.Body.List[len(n.Body.List)-1]
+				if ret, ok := last.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+					if ident, ok := ret.Results[0].(*ast.Ident); ok {
+						p.ctors[n.Name.Name] = &ctor{file: file, name: ident.Name, ret: fset.Position(ret.Pos()).Offset}
+					}
+				}
+			}
+
+			if n.Name.Name != "GetCommands" {
+				return true
+			}
+
+			for _, stmt := range n.Body.List {
+				if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+					if lit, ok := ret.Results[0].(*ast.CompositeLit); ok {
+						p.commands = &literal{file: file, rbrace: fset.Position(lit.Rbrace).Offset}
+						p.inspectInlines(fset, file, lit)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, ident := range n.Names {
+				if ident.Name != "Langs" || i >= len(n.Values) {
+					continue
+				}
+
+				if lit, ok := n.Values[i].(*ast.CompositeLit); ok {
+					p.langs = &literal{file: file, rbrace: fset.Position(lit.Rbrace).Offset}
+				}
+			}
+		}
+
+		return true
+	})
+}
+
+// endOfFile offset of edit append to file
+const endOfFile = -1
+
+// edit insert code before closing brace of literal, before statement, or at end of file,
+// replace code between offset and end if end is set
+type edit struct {
+	offset int
+	end    int
+	code   string
+	list   bool // element of literal, comma is added after previous element
+}
+
+// inspectInlines find commands registered by cli.NewCmd or cli.NewCmdE in literal of GetCommands
+func (p *cmdPackage) inspectInlines(fset *token.FileSet, file string, lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		call, ok := elt.(*ast.CallExpr)
+		if !ok || len(call.Args) < 3 {
+			continue
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "NewCmd" && sel.Sel.Name != "NewCmdE") {
+			continue
+		}
+
+		name, ok := stringLit(call.Args[0])
+		if !ok {
+			continue
+		}
+
+		in := &inline{file: file, start: fset.Position(call.Pos()).Offset, end: fset.Position(call.End()).Offset}
+		if ident, ok := call.Args[2].(*ast.Ident); ok {
+			in.handler = ident.Name
+		}
+
+		p.inlines[name] = in
+	}
+}
+
+// applyEdits apply edits from the end of file, return formatted source
+func applyEdits(file string, edits []edit) ([]byte, error) {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range edits {
+		if edits[i].offset == endOfFile {
+			edits[i].offset = len(src)
+		}
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].offset > edits[j].offset
+	})
+
+	for _, e := range edits {
+		buf := &bytes.Buffer{}
+		if e.end > e.offset {
+			buf.Write(src[:e.offset])
+			buf.WriteString(e.code)
+			buf.Write(src[e.end:])
+			src = buf.Bytes()
+			continue
+		}
+
+		// keep trailing comma of last element
+		head := bytes.TrimRight(src[:e.offset], " \t\n")
+		tail := src[e.offset:]
+		buf.Write(head)
+		if e.list && len(head) > 0 && head[len(head)-1] != ',' && head[len(head)-1] != '{' {
+			buf.WriteString(",")
+		}
+
+		buf.WriteString("\n")
+		buf.WriteString(e.code)
+		buf.Write(tail)
+		src = buf.Bytes()
+	}
+
+	return format.Source(src)
+}
+
+// fileName return file name with order, order follow the last command of same group,
+// or start a new group after all groups
+func (p *cmdPackage) fileName(name string, group string) string {
+	if len(p.orders) == 0 {
+		return name + ".go"
+	}
+
+	orders := make([]int, 0)
+	maxOrder := 0
+	for cmd, order := range p.orders {
+		if p.groups[cmd] == group {
+			orders = append(orders, order)
+		}
+
+		if order > maxOrder {
+			maxOrder = order
+		}
+	}
+
+	order := (maxOrder/10+1)*10 + 1
+	if len(orders) > 0 {
+		sort.Ints(orders)
+		order = orders[len(orders)-1] + 1
+	}
+
+	return fmt.Sprintf("%02d%s.go", order, name)
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// upperCamel return like ApiResources
+func upperCamel(name string) string {
+	parts := strings.Split(name, "-")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// lowerCamel return like apiResources
+func lowerCamel(name string) string {
+	upper := upperCamel(name)
+	if upper == "" {
+		return upper
+	}
+
+	return strings.ToLower(upper[:1]) + upper[1:]
+}
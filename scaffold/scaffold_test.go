@@ -0,0 +1,128 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateNested(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"commands.go": "package cmds\n\nimport \"github.com/jeckbjy/cli\"\n\nfunc GetCommands() []*cli.Command {\n\treturn []*cli.Command{}\n}\n",
+		"language.go": "package cmds\n\nvar Langs = map[string]string{}\n",
+		"11get.go":    "package cmds\n\nfunc onCmdGet() {}\n",
+	}
+
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Generate(Config{Dir: dir, Name: "rollout status"}); err == nil {
+		t.Errorf("nested command without parent should fail")
+	}
+
+	parent, err := Generate(Config{Dir: dir, Name: "rollout", Group: "Deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Generate(Config{Dir: dir, Name: "rollout status", Short: "Show status"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(parent) != "21rollout.go" || filepath.Base(path) != "21rollout-status.go" {
+		t.Errorf("bad file name %s, %s", parent, path)
+	}
+
+	if _, err := Generate(Config{Dir: dir, Name: "rollout status"}); err == nil {
+		t.Errorf("existing nested command should fail")
+	}
+
+	expects := map[string][]string{
+		parent:                            {"cmd.AddSub(newCmdRolloutStatus())\n\treturn cmd"},
+		path:                              {`cli.NewCmd("status", "Other", onCmdRolloutStatus)`, `cmd.Short = "$rollout-status_s"`},
+		filepath.Join(dir, "language.go"): {`"rollout-status_s": "Show status"`, "rolloutStatusLongDesc"},
+		filepath.Join(dir, "commands.go"): {"newCmdRollout()"},
+	}
+
+	for file, subs := range expects {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, sub := range subs {
+			if !strings.Contains(string(data), sub) {
+				t.Errorf("%s should contain %s:\n%s", filepath.Base(file), sub, data)
+			}
+		}
+	}
+
+	data, _ := ioutil.ReadFile(filepath.Join(dir, "commands.go"))
+	if strings.Contains(string(data), "newCmdRolloutStatus") {
+		t.Errorf("nested command should not be registered in GetCommands")
+	}
+}
+
+func TestGenerateNestedInline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scaffold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"commands.go":  "package cmds\n\nimport \"github.com/jeckbjy/cli\"\n\nfunc GetCommands() []*cli.Command {\n\treturn []*cli.Command{\n\t\tcli.NewCmd(\"get\", \"Basic\", onCmdGet),\n\t\tcli.NewCmd(\"rollout\", \"Deploy\", onCmdRollout),\n\t}\n}\n",
+		"language.go":  "package cmds\n\nvar Langs = map[string]string{}\n",
+		"21rollout.go": "package cmds\n\nimport \"github.com/jeckbjy/cli\"\n\nfunc onCmdRollout(ctx *cli.Context) {\n}\n",
+	}
+
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, err := Generate(Config{Dir: dir, Name: "rollout status"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Generate(Config{Dir: dir, Name: "rollout history"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(path) != "21rollout-status.go" {
+		t.Errorf("bad file name %s", path)
+	}
+
+	expects := map[string][]string{
+		"commands.go": {`cli.NewCmd("get", "Basic", onCmdGet),`, "newCmdRollout(),"},
+		"21rollout.go": {"func newCmdRollout() *cli.Command {\n\tcmd := cli.NewCmd(\"rollout\", \"Deploy\", onCmdRollout)\n" +
+			"\tcmd.AddSub(newCmdRolloutStatus())\n\tcmd.AddSub(newCmdRolloutHistory())\n\treturn cmd\n}"},
+	}
+
+	for file, subs := range expects {
+		data, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, sub := range subs {
+			if !strings.Contains(string(data), sub) {
+				t.Errorf("%s should contain %s:\n%s", file, sub, data)
+			}
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Spec command tree described by file, handlers are bound by command path,
+// json is supported, other formats like yaml need a decoder, see SetSpecDecoder
+// example:
+//
+//	{
+//	  "groups": {"Basic": "Basic Commands (Beginner):"},
+//	  "languages": {"create_s": "Create a resource from a file or from stdin"},
+//	  "flags": [{"name": "verbose", "short": "v"}],
+//	  "commands": [{
+//	    "name": "create", "group": "Basic", "short": "$create_s",
+//	    "flags": [{"name": "filename", "short": "f", "param": "path", "required": true}],
+//	    "commands": [{"name": "deployment", "args": [{"name": "name", "required": true}]}]
+//	  }]
+//	}
+type Spec struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Header    string            `json:"header"`
+	Footer    string            `json:"footer"`
+	Groups    map[string]string `json:"groups"`
+	Languages map[string]string `json:"languages"`
+	Flags     []*FlagSpec       `json:"flags"`
+	Commands  []*CommandSpec    `json:"commands"`
+}
+
+// CommandSpec command of spec
+type CommandSpec struct {
+	Name       string         `json:"name"`
+	Group      string         `json:"group"`
+	Short      string         `json:"short"`
+	Long       string         `json:"long"`
+	Header     string         `json:"header"`
+	Footer     string         `json:"footer"`
+	Alias      []string       `json:"alias"`
+	Hidden     bool           `json:"hidden"`
+	Deprecated string         `json:"deprecated"`
+	Flags      []*FlagSpec    `json:"flags"`
+	Args       []*ArgSpec     `json:"args"`
+	Commands   []*CommandSpec `json:"commands"`
+}
+
+// FlagSpec flag of spec
+type FlagSpec struct {
+	Name     string   `json:"name"`
+	Short    string   `json:"short"`
+	Default  string   `json:"default"`
+	Param    string   `json:"param"`
+	Usage    string   `json:"usage"`
+	Required bool     `json:"required"`
+	Multiple bool     `json:"multiple"`
+	Secret   bool     `json:"secret"`
+	Hidden   bool     `json:"hidden"`
+	Env      string   `json:"env"`
+	Enum     []string `json:"enum"`
+}
+
+// ArgSpec positional argument of spec
+type ArgSpec struct {
+	Name     string `json:"name"`
+	Usage    string `json:"usage"`
+	Required bool   `json:"required"`
+	Rest     bool   `json:"rest"`
+}
+
+// Handlers handler registry by command path, like 'create deployment',
+// value must be Action, ActionE or Runner
+type Handlers map[string]interface{}
+
+// SpecDecoder decode spec file into *Spec, like yaml.Unmarshal
+type SpecDecoder func(data []byte, v interface{}) error
+
+// SetSpecDecoder set decoder of spec files with extensions, so yaml is supported without dependency,
+// field names of spec are lower case of struct fields, which is the default of most yaml packages
+// example:
+//
+//	app.SetSpecDecoder(yaml.Unmarshal, ".yaml", ".yml")
+func (app *App) SetSpecDecoder(decoder SpecDecoder, exts ...string) {
+	if app.specDecoders == nil {
+		app.specDecoders = make(map[string]SpecDecoder)
+	}
+
+	for _, ext := range exts {
+		app.specDecoders[strings.ToLower(ext)] = decoder
+	}
+}
+
+// LoadSpec load spec file and bind handlers, decoded by extension, see SetSpecDecoder and LoadSpecData
+func (app *App) LoadSpec(path string, handlers Handlers) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	decoder := app.specDecoders[ext]
+	if decoder == nil && (ext == ".yaml" || ext == ".yml") {
+		return fmt.Errorf("spec %s: yaml decoder is not set, see SetSpecDecoder", path)
+	}
+
+	if decoder == nil {
+		err = app.LoadSpecData(data, handlers)
+	} else {
+		spec := &Spec{}
+		if err = decoder(data, spec); err == nil {
+			err = app.LoadSpecTree(spec, handlers)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("spec %s: %+v", path, err)
+	}
+
+	return nil
+}
+
+// LoadSpecData load json spec and bind handlers,
+// fail if command without sub commands has no handler, or handler has no command
+func (app *App) LoadSpecData(data []byte, handlers Handlers) error {
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return err
+	}
+
+	return app.LoadSpecTree(spec, handlers)
+}
+
+// LoadSpecTree build command tree of spec with builder, so spec is validated as App.Command
+func (app *App) LoadSpecTree(spec *Spec, handlers Handlers) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(error)
+			if !ok {
+				e = fmt.Errorf("%+v", r)
+			}
+
+			err = e
+		}
+	}()
+
+	if spec.Name != "" {
+		app.Name = spec.Name
+	}
+
+	if spec.Version != "" {
+		app.Version = spec.Version
+	}
+
+	app.AddHeader(spec.Header)
+	app.AddFooter(spec.Footer)
+	for name, desc := range spec.Groups {
+		app.AddGroup(name, desc)
+	}
+
+	if len(spec.Languages) > 0 && app.languages == nil {
+		app.languages = make(map[string]string)
+	}
+
+	for key, value := range spec.Languages {
+		app.languages[key] = value
+	}
+
+	for _, f := range spec.Flags {
+		if f.Name == "" {
+			return fmt.Errorf("global flag name is empty")
+		}
+	}
+
+	app.AddFlags(specFlags(spec.Flags))
+
+	used := make(map[string]bool)
+	var errs []string
+	for _, cs := range spec.Commands {
+		app.loadSpecCommand(app.Command(cs.Name), cs, cs.Name, handlers, used, &errs)
+	}
+
+	for path := range handlers {
+		if !used[path] {
+			errs = append(errs, fmt.Sprintf("handler '%s' has no command", path))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// loadSpecCommand fill command and sub commands, record mismatched handlers
+func (app *App) loadSpecCommand(b *CommandBuilder, cs *CommandSpec, path string, handlers Handlers, used map[string]bool, errs *[]string) {
+	b.Group(cs.Group).Short(cs.Short).Long(cs.Long).Header(cs.Header).Footer(cs.Footer).Deprecated(cs.Deprecated)
+	b.Alias(cs.Alias...)
+	if cs.Hidden {
+		b.Hidden()
+	}
+
+	b.Flag(specFlags(cs.Flags)...)
+	for _, as := range cs.Args {
+		b.Arg(&Arg{Name: as.Name, Usage: as.Usage, Required: as.Required, Rest: as.Rest})
+	}
+
+	handler, ok := handlers[path]
+	if ok {
+		used[path] = true
+		switch h := handler.(type) {
+		case Action:
+			b.Action(h)
+		case func(*Context):
+			b.Action(h)
+		case ActionE:
+			b.ActionE(h)
+		case func(*Context) error:
+			b.ActionE(h)
+		case Runner:
+			b.ActionE(runnerAction(b.Cmd(), h))
+		default:
+			*errs = append(*errs, fmt.Sprintf("handler '%s' has unsupported type %T", path, handler))
+		}
+	} else if len(cs.Commands) == 0 {
+		*errs = append(*errs, fmt.Sprintf("command '%s' has no handler", path))
+	}
+
+	for _, sub := range cs.Commands {
+		subPath := strings.TrimSpace(path + " " + sub.Name)
+		b.Sub(sub.Name, func(sb *CommandBuilder) {
+			app.loadSpecCommand(sb, sub, subPath, handlers, used, errs)
+		})
+	}
+}
+
+func specFlags(specs []*FlagSpec) []*Flag {
+	flags := make([]*Flag, 0, len(specs))
+	for _, fs := range specs {
+		flags = append(flags, &Flag{
+			Name:     fs.Name,
+			Short:    fs.Short,
+			Value:    fs.Default,
+			Param:    fs.Param,
+			Usage:    fs.Usage,
+			Required: fs.Required,
+			Multiple: fs.Multiple,
+			Secret:   fs.Secret,
+			Hidden:   fs.Hidden,
+			Env:      fs.Env,
+			Enum:     fs.Enum,
+		})
+	}
+
+	return flags
+}
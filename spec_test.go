@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSpecDecoder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// json is valid yaml, decoded by json to avoid dependency
+	path := filepath.Join(dir, "spec.yaml")
+	data := `{"commands": [{"name": "get", "args": [{"name": "resource"}]}]}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var ran bool
+	handlers := Handlers{"get": Action(func(ctx *Context) { ran = ctx.Arg(0) == "pods" })}
+	if err := New().LoadSpec(path, handlers); err == nil {
+		t.Errorf("yaml without decoder should fail")
+	}
+
+	app := New()
+	app.SetSpecDecoder(json.Unmarshal, ".yaml", ".yml")
+	if err := app.LoadSpec(path, handlers); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.RunArgs([]string{"t", "get", "pods"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ran {
+		t.Errorf("handler of decoded spec should run")
+	}
+}
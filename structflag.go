@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseFlags generate flags from tagged struct, same name rule as Context.BindE,
+// field with `cli:"-"` or type can not be bound is ignored, slice and map field enable Multiple
+// example:
+//
+//	type CreateFlags struct {
+//		DryRun   bool   `cli:"dry-run" usage:"only print the object"`
+//		Filename string `cli:"filename" short:"f" param:"path" required:"true"`
+//		Output   string `short:"o" default:"yaml" enum:"json,yaml" env:"KUBECTL_OUTPUT"`
+//		Debug    bool   `hidden:"true"`
+//	}
+func ParseFlags(v interface{}) ([]*Flag, error) {
+	vtype := reflect.TypeOf(v)
+	if vtype != nil && vtype.Kind() == reflect.Ptr {
+		vtype = vtype.Elem()
+	}
+
+	if vtype == nil || vtype.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parse flags just support struct")
+	}
+
+	flags := make([]*Flag, 0, vtype.NumField())
+	if err := parseStructFlags(vtype, "", &flags); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// parseStructFlags generate flags of struct, nested struct use name as prefix
+func parseStructFlags(vtype reflect.Type, prefix string, flags *[]*Flag) error {
+	for i := 0; i < vtype.NumField(); i++ {
+		field := vtype.Field(i)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+
+		if _, isArg := field.Tag.Lookup("arg"); isArg {
+			continue
+		}
+
+		if isNestedField(field) {
+			ftype := field.Type
+			if ftype.Kind() == reflect.Ptr {
+				ftype = ftype.Elem()
+			}
+
+			if err := parseStructFlags(ftype, nestedPrefix(field, prefix, name), flags); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !isFieldType(field.Type) {
+			continue
+		}
+
+		flag := &Flag{
+			Name:  prefix + name,
+			Short: field.Tag.Get("short"),
+			Value: field.Tag.Get("default"),
+			Param: field.Tag.Get("param"),
+			Usage: field.Tag.Get("usage"),
+			Env:   field.Tag.Get("env"),
+		}
+
+		var err error
+		if flag.Required, err = parseBoolTag(field, "required"); err != nil {
+			return err
+		}
+
+		if flag.Hidden, err = parseBoolTag(field, "hidden"); err != nil {
+			return err
+		}
+
+		if flag.Secret, err = parseBoolTag(field, "secret"); err != nil {
+			return err
+		}
+
+		if enum := field.Tag.Get("enum"); enum != "" {
+			flag.Enum = strings.Split(enum, ",")
+		}
+
+		kind := field.Type.Kind()
+		flag.Multiple = (kind == reflect.Slice || kind == reflect.Map) && !isUnmarshaler(field.Type)
+
+		*flags = append(*flags, flag)
+	}
+
+	return nil
+}
+
+func parseBoolTag(field reflect.StructField, key string) (bool, error) {
+	tag := field.Tag.Get(key)
+	if tag == "" {
+		return false, nil
+	}
+
+	v, err := strconv.ParseBool(tag)
+	if err != nil {
+		return false, fmt.Errorf("bad tag %s of field %s, %+v", key, field.Name, err)
+	}
+
+	return v, nil
+}
+
+// FlagSource return flags without reflection, such as code generated by bindgen
+type FlagSource interface {
+	Flags() []*Flag
+}
+
+// AddStructFlags add flags generated from tagged struct, panic if tags are invalid,
+// bind values with Context.Bind and the same struct, FlagSource is used if implemented
+func (cmd *Command) AddStructFlags(v interface{}) {
+	if src, ok := v.(FlagSource); ok {
+		cmd.Flags = append(cmd.Flags, src.Flags()...)
+		return
+	}
+
+	flags, err := ParseFlags(v)
+	if err != nil {
+		panic(err)
+	}
+
+	cmd.Flags = append(cmd.Flags, flags...)
+}
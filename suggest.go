@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestDistance default max edit distance of suggestions
+const DefaultSuggestDistance = 2
+
+// editDistance return the Damerau-Levenshtein(optimal string alignment) distance,
+// insert, delete, substitute and transpose of two adjacent chars all cost 1
+func editDistance(a, b string) int {
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+	la, lb := len(a), len(b)
+	if la == 0 {
+		return lb
+	}
+
+	if lb == 0 {
+		return la
+	}
+
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[la][lb]
+}
+
+func minInt(first int, others ...int) int {
+	m := first
+	for _, v := range others {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+// suggest return candidates similar to name, order by distance,
+// candidate which has name as prefix is also suggested
+func suggest(name string, candidates []string, max int) []string {
+	if name == "" || max < 0 {
+		return nil
+	}
+
+	type item struct {
+		name string
+		dist int
+	}
+
+	items := make([]item, 0)
+	exists := make(map[string]bool)
+	for _, c := range candidates {
+		if c == "" || exists[c] {
+			continue
+		}
+
+		dist := editDistance(name, c)
+		if dist > max && !strings.HasPrefix(strings.ToLower(c), strings.ToLower(name)) {
+			continue
+		}
+
+		exists[c] = true
+		items = append(items, item{name: c, dist: dist})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].dist < items[j].dist
+	})
+
+	results := make([]string, 0, len(items))
+	for _, it := range items {
+		results = append(results, it.name)
+	}
+
+	return results
+}
+
+// suggestCommands return similar names of visible sub commands, alias included
+func (app *App) suggestCommands(cmd *Command, name string) []string {
+	candidates := make([]string, 0, len(cmd.Subs))
+	for _, sub := range cmd.Subs {
+		if sub.Hidden || sub.Name == AppCommandName {
+			continue
+		}
+
+		candidates = append(candidates, sub.Name)
+		candidates = append(candidates, sub.Alias...)
+	}
+
+	return suggest(name, candidates, app.distance)
+}
+
+// suggestFlags return similar long names of flags in command chain, with -- prefix
+func (app *App) suggestFlags(cmds []*Command, name string) []string {
+	candidates := make([]string, 0)
+	for _, cmd := range cmds {
+		for _, f := range cmd.Flags {
+			if f.Name != "" {
+				candidates = append(candidates, f.Name)
+			}
+		}
+	}
+
+	results := suggest(name, candidates, app.distance)
+	for i, r := range results {
+		results[i] = "--" + r
+	}
+
+	return results
+}
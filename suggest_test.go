@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newSuggestApp(args *[]string) *App {
+	app := New()
+	app.AddCommands([]*Command{
+		{Name: "repo", Subs: []*Command{NewCmd("list", "", func(ctx *Context) {})}},
+		{Name: "get", Run: func(ctx *Context) { *args = ctx.Args() }, Subs: []*Command{NewCmd("all", "", func(ctx *Context) {})}},
+	})
+
+	return app
+}
+
+func TestUnknownSubcommand(t *testing.T) {
+	var args []string
+	app := newSuggestApp(&args)
+	err := app.RunArgs([]string{"t", "repo", "lst"})
+	uerr, ok := err.(*UnknownCommandError)
+	if !ok {
+		t.Fatalf("expect UnknownCommandError, got %+v", err)
+	}
+
+	if uerr.Name != "lst" || !reflect.DeepEqual(uerr.Suggestions, []string{"list"}) {
+		t.Errorf("bad error %+v", uerr)
+	}
+
+	// command with action accept unknown token as arg
+	if err := app.RunArgs([]string{"t", "get", "pods"}); err != nil || !reflect.DeepEqual(args, []string{"pods"}) {
+		t.Errorf("token should be arg of runnable command, %+v, %q", err, args)
+	}
+}
+
+func TestShortFlag(t *testing.T) {
+	var output string
+	app := New()
+	cmd := NewCmd("get", "", func(ctx *Context) { output = ctx.FlagStr("output") })
+	cmd.Flags = []*Flag{{Name: "output", Short: "o"}}
+	app.AddCommands([]*Command{cmd})
+	if err := app.RunArgs([]string{"t", "get", "-o", "json"}); err != nil || output != "json" {
+		t.Errorf("short flag should be found, %+v, %s", err, output)
+	}
+
+	err := app.RunArgs([]string{"t", "get", "--outptu=json"})
+	if ferr, ok := err.(*UnknownFlagError); !ok || !reflect.DeepEqual(ferr.Suggestions, []string{"--output"}) {
+		t.Errorf("expect suggestion of --output, %+v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 func strToInt(s string) int {
@@ -85,3 +87,69 @@ func toKebabCase(str string) string {
 	snake = matchAllCap.ReplaceAllString(snake, "${1}-${2}")
 	return strings.ToLower(snake)
 }
+
+// splitArgs split string into args like shell, support quote by ' and ", and escape by \
+func splitArgs(str string) ([]string, error) {
+	args := make([]string, 0)
+	builder := strings.Builder{}
+	hasArg := false
+	var quote rune
+
+	runes := []rune(str)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		switch {
+		case ch == '\\' && quote != '\'':
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("unexpected end of escape: %s", str)
+			}
+
+			i++
+			builder.WriteRune(runes[i])
+			hasArg = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				builder.WriteRune(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+			hasArg = true
+		case unicode.IsSpace(ch):
+			if hasArg {
+				args = append(args, builder.String())
+				builder.Reset()
+				hasArg = false
+			}
+		default:
+			builder.WriteRune(ch)
+			hasArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote: %s", str)
+	}
+
+	if hasArg {
+		args = append(args, builder.String())
+	}
+
+	return args, nil
+}
+
+// joinArgs join args to string which can be split by splitArgs
+func joinArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\r\n'\"\\") {
+			quoted = append(quoted, arg)
+			continue
+		}
+
+		quoted = append(quoted, "'"+strings.Replace(arg, "'", `'\''`, -1)+"'")
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// regexps cache compiled regexp of validation tag
+var regexps sync.Map
+
+// validateStruct validate all fields after binding, nil pointer and fields fail to bind are skipped,
+// so conversion errors and violations are reported together
+func (c *Context) validateStruct(value reflect.Value, prefix string, layout *argLayout, berr *BindError) {
+	vtype := value.Type()
+	for i := 0; i < vtype.NumField(); i++ {
+		sf := vtype.Field(i)
+		name, ok := fieldName(sf)
+		if !ok {
+			continue
+		}
+
+		field := value.Field(i)
+		if tag, ok := sf.Tag.Lookup("arg"); ok {
+			pos := layout.position(tag)
+			if pos == -1 {
+				pos = layout.rest
+			}
+
+			if berr.isInvalid("", pos) {
+				continue
+			}
+
+			target := fmt.Sprintf("argument #%d <%s>", pos+1, argName(sf, tag))
+			berr.Errors = append(berr.Errors, validateField(field, sf, target, pos < len(c.args))...)
+			continue
+		}
+
+		if isNestedField(sf) {
+			nested := nestedPrefix(sf, prefix, name)
+			if sf.Type.Kind() != reflect.Ptr {
+				c.validateStruct(field, nested, layout, berr)
+			} else if !field.IsNil() {
+				c.validateStruct(field.Elem(), nested, layout, berr)
+			} else {
+				layout.walk(sf.Type.Elem())
+			}
+
+			continue
+		}
+
+		if berr.isInvalid(prefix+name, 0) {
+			continue
+		}
+
+		f := c.Flag(prefix + name)
+		supplied := f != nil && (f.Len() > 0 || f.Used())
+		berr.Errors = append(berr.Errors, validateField(field, sf, "flag --"+prefix+name, supplied)...)
+	}
+}
+
+// validateField check validation tags of field, return all violations
+//   - `nonempty:"true"` string, slice and map can not be empty, pointer can not be nil
+//   - `min:"1"` `max:"10"` limit number and duration, or length of string, slice and map
+//   - `oneof:"a,b,c"` value must be one of the list
+//   - `regex:"^[a-z]+$"` value must match the regexp
+//   - `file-exists:"true"` `dir-exists:"true"` value must be an existing file or dir
+//
+// oneof, regex, file-exists and dir-exists check each element of slice,
+// rules except nonempty are skipped if value is not supplied after defaults and env
+func validateField(field reflect.Value, sf reflect.StructField, target string, supplied bool) []error {
+	errs := make([]error, 0)
+	fail := func(rule string, format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Target: target, Rule: rule, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if isTrueTag(sf, "nonempty") && isEmptyValue(field) {
+		fail("nonempty", "can not be empty")
+	}
+
+	if !supplied {
+		return errs
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return errs
+		}
+
+		field = field.Elem()
+	}
+
+	for _, rule := range []string{"min", "max"} {
+		tag := sf.Tag.Get(rule)
+		if tag == "" {
+			continue
+		}
+
+		ok, err := checkRange(field, rule, tag)
+		if err != nil {
+			fail(rule, "bad tag %s:%q, %+v", rule, tag, err)
+		} else if !ok {
+			if rule == "min" {
+				fail(rule, "must be at least %s", tag)
+			} else {
+				fail(rule, "must be at most %s", tag)
+			}
+		}
+	}
+
+	elems := []reflect.Value{field}
+	if field.Kind() == reflect.Slice && !isUnmarshaler(field.Type()) {
+		elems = elems[:0]
+		for i := 0; i < field.Len(); i++ {
+			elems = append(elems, field.Index(i))
+		}
+	}
+
+	for _, elem := range elems {
+		str := fmt.Sprint(elem.Interface())
+		if tag := sf.Tag.Get("oneof"); tag != "" && !containsStr(strings.Split(tag, ","), str) {
+			fail("oneof", "'%s' is not one of %s", str, strings.Replace(tag, ",", "|", -1))
+		}
+
+		if tag := sf.Tag.Get("regex"); tag != "" {
+			re, err := compileRegexp(tag)
+			if err != nil {
+				fail("regex", "bad tag regex:%q, %+v", tag, err)
+			} else if !re.MatchString(str) {
+				fail("regex", "'%s' does not match %s", str, tag)
+			}
+		}
+
+		if isTrueTag(sf, "file-exists") {
+			if info, err := os.Stat(str); err != nil || info.IsDir() {
+				fail("file-exists", "file '%s' does not exist", str)
+			}
+		}
+
+		if isTrueTag(sf, "dir-exists") {
+			if info, err := os.Stat(str); err != nil || !info.IsDir() {
+				fail("dir-exists", "dir '%s' does not exist", str)
+			}
+		}
+	}
+
+	return errs
+}
+
+// checkRange compare number, duration or length with tag
+func checkRange(field reflect.Value, rule string, tag string) (bool, error) {
+	var value, limit float64
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(tag)
+		if err != nil {
+			return false, err
+		}
+
+		value, limit = float64(field.Int()), float64(d)
+	default:
+		l, err := strconv.ParseFloat(tag, 64)
+		if err != nil {
+			return false, err
+		}
+
+		limit = l
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value = float64(field.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = float64(field.Uint())
+		case reflect.Float32, reflect.Float64:
+			value = field.Float()
+		case reflect.String, reflect.Slice, reflect.Map:
+			value = float64(field.Len())
+		default:
+			return false, fmt.Errorf("not support type:%+v", field.Type())
+		}
+	}
+
+	if rule == "min" {
+		return value >= limit, nil
+	}
+
+	return value <= limit, nil
+}
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexps.Store(pattern, re)
+	return re, nil
+}
+
+func isTrueTag(sf reflect.StructField, key string) bool {
+	v, _ := strconv.ParseBool(sf.Tag.Get(key))
+	return v
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
+
+func containsStr(list []string, str string) bool {
+	for _, v := range list {
+		if v == str {
+			return true
+		}
+	}
+
+	return false
+}
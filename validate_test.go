@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+type validateFlags struct {
+	Output   string `oneof:"json,yaml"`
+	Port     int    `min:"1" max:"65535"`
+	Name     string `regex:"^[a-z]+$"`
+	File     string `file-exists:"true"`
+	Dir      string `dir-exists:"true"`
+	Resource string `arg:"0" oneof:"pods,nodes"`
+}
+
+// runValidate run command binding validateFlags, return bind error
+func runValidate(t *testing.T, args ...string) error {
+	var bindErr error
+	app := New()
+	cmd := NewCmd("get", "", func(ctx *Context) {
+		var flags validateFlags
+		bindErr = ctx.BindE(&flags)
+	})
+	cmd.AddStructFlags(&validateFlags{})
+	app.AddCommands([]*Command{cmd})
+	if err := app.RunArgs(append([]string{"t", "get"}, args...)); err != nil {
+		t.Fatalf("run fail, %+v", err)
+	}
+
+	return bindErr
+}
+
+func TestValidateOmitted(t *testing.T) {
+	if err := runValidate(t); err != nil {
+		t.Errorf("omitted flags and args should not be validated, %+v", err)
+	}
+}
+
+func TestValidateSupplied(t *testing.T) {
+	if err := runValidate(t, "pods", "--output=json", "--port=80", "--name=abc", "--file=validate.go", "--dir=completion"); err != nil {
+		t.Errorf("valid values should pass, %+v", err)
+	}
+
+	err := runValidate(t, "svc", "--output=xml", "--port=0", "--name=A1", "--file=nope", "--dir=validate.go")
+	if err == nil {
+		t.Fatal("invalid values should fail")
+	}
+
+	if _, ok := err.(*BindError); !ok {
+		t.Errorf("violations should be aggregated into BindError, %+v", err)
+	}
+
+	for _, target := range []string{"flag --output", "flag --port", "flag --name", "flag --file", "flag --dir", "argument #1 <resource>"} {
+		if !strings.Contains(err.Error(), target) {
+			t.Errorf("error should name %s, %+v", target, err)
+		}
+	}
+}
+
+func TestValidateNonemptyOmitted(t *testing.T) {
+	var bindErr error
+	app := New()
+	cmd := NewCmd("get", "", func(ctx *Context) {
+		var flags struct {
+			Name string `nonempty:"true"`
+		}
+
+		bindErr = ctx.BindE(&flags)
+	})
+	cmd.Flags = []*Flag{{Name: "name"}}
+	app.AddCommands([]*Command{cmd})
+	if err := app.RunArgs([]string{"t", "get"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if bindErr == nil {
+		t.Errorf("nonempty should fail if omitted")
+	}
+}
+
+func TestValidateWithBindError(t *testing.T) {
+	err := runValidate(t, "svc", "--output=xml", "--port=abc")
+	berr, ok := err.(*BindError)
+	if !ok {
+		t.Fatalf("expect BindError, got %+v", err)
+	}
+
+	var invalid, violations int
+	for _, e := range berr.Errors {
+		switch e.(type) {
+		case *InvalidValueError:
+			invalid++
+		case *ValidationError:
+			violations++
+		}
+	}
+
+	// port fail to convert is not validated again
+	if invalid != 1 || violations != 2 {
+		t.Errorf("expect 1 conversion error and 2 violations, %+v", err)
+	}
+}